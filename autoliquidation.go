@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// AUTOLIQUIDATION ET ACQUISITIONS INTRACOMMUNAUTAIRES
+// ========================================
+
+// L'acheteur déclare la TVA due (collectée) et la déduit dans la même
+// déclaration : l'effet net est nul si la déduction est totale.
+
+type operationAutoliquidee struct {
+	libelle   string
+	court     string // Libellé de l'historique
+	compteDue string // Compte de la TVA autoliquidée
+}
+
+var OperationsAutoliquidees = []operationAutoliquidee{
+	{"Acquisition intracommunautaire de biens", "acquisition intracommunautaire", "4452"},
+	{"Prestation de services d'un prestataire de l'UE", "prestation UE", "4452"},
+	{"Sous-traitance du BTP (art. 283-2 nonies)", "sous-traitance BTP", "44571"},
+	{"Importation (autoliquidation à l'import)", "importation", "44571"},
+}
+
+type resultatAutoliquidation struct {
+	due, deductible float64
+	net             float64 // TVA restant à payer (déduction partielle)
+}
+
+// TVA due sur le HT, déductible selon le droit à déduction en %
+func calculerAutoliquidation(ht, taux, deduction float64, decimales int) resultatAutoliquidation {
+	due := arrondir(ht*taux/100, decimales)
+	deductible := arrondir(due*deduction/100, decimales)
+	return resultatAutoliquidation{due, deductible, arrondir(due-deductible, decimales)}
+}
+
+// Charge (HT + TVA non déductible) et TVA déductible au débit ; fournisseur
+// (HT) et TVA autoliquidée au crédit
+func ecritureAutoliquidation(op operationAutoliquidee, ht float64, r resultatAutoliquidation,
+	journal, compteCharge, compteDeductible, compteFournisseur string) ecriture {
+	e := ecriture{journal: journal, journalLib: LibellesJournaux[SensAchat]}
+	libelle := "Autoliquidation " + op.court
+	for _, l := range []struct {
+		compte  string
+		montant float64
+		debit   bool
+	}{
+		{compteCharge, ht + r.net, true},
+		{compteDeductible, r.deductible, true},
+		{compteFournisseur, ht, false},
+		{op.compteDue, r.due, false},
+	} {
+		if l.montant != 0 {
+			e.lignes = append(e.lignes, nouvelleLigneEcriture(l.compte, libelle, l.montant, l.debit))
+		}
+	}
+	return e
+}
+
+// ========================================
+// FENÊTRE AUTOLIQUIDATION
+// ========================================
+
+func (c *Calculatrice) fenetreAutoliquidation() {
+	w := c.nouvelleFenetre("Autoliquidation de la TVA", 850, 650)
+	decimales := c.reglages.decimales()
+
+	var libelles []string
+	for _, op := range OperationsAutoliquidees {
+		libelles = append(libelles, op.libelle)
+	}
+	selectOperation := widget.NewSelect(libelles, nil)
+	selectOperation.SetSelectedIndex(0)
+	entreeHT := widget.NewEntry()
+	entreeHT.SetPlaceHolder("Montant HT")
+	if v := c.obtenirValeurCourante(); v > 0 {
+		entreeHT.SetText(c.formaterNombre(c.valeurCourante))
+	}
+	selectTaux := widget.NewSelect(c.reglages.libellesTauxTVA(), nil)
+	selectTaux.SetSelectedIndex(0)
+	entreeDeduction := widget.NewEntry()
+	entreeDeduction.SetText("100")
+
+	entreeDate := widget.NewEntry()
+	entreeDate.SetText(formaterDate(aujourdhui()))
+	entreePiece := widget.NewEntry()
+	entreePiece.SetPlaceHolder("Référence de la pièce")
+	entreeCharge, champCharge := c.champCompte(w)
+	entreeCharge.SetText(c.reglages.Comptes.Charge)
+	entreeFournisseur, champFournisseur := c.champCompte(w)
+	entreeFournisseur.SetText(c.reglages.Comptes.Fournisseur)
+	checkImmobilisation := widget.NewCheck(fmt.Sprintf("Immobilisation (TVA déductible en %s)", c.reglages.Comptes.TVADeductibleImmo), func(coche bool) {
+		cpt := c.reglages.Comptes
+		if coche && strings.HasPrefix(entreeCharge.Text, "6") {
+			entreeCharge.SetText(cpt.Immobilisation)
+		} else if !coche && entreeCharge.Text == cpt.Immobilisation {
+			entreeCharge.SetText(cpt.Charge)
+		}
+	})
+
+	resultat := widget.NewLabel("")
+	resultat.TextStyle = fyne.TextStyle{Bold: true}
+	t := &tableau{
+		titre:   "Autoliquidation",
+		entetes: []string{"Journal", "Date", "Pièce", "Compte", "Intitulé", "Libellé", "Débit", "Crédit"},
+	}
+	table := nouveauTableauWidget(t)
+
+	var courante *ecriture
+	calculer := func(versHistorique bool) error {
+		op := OperationsAutoliquidees[selectOperation.SelectedIndex()]
+		ht, err := lireNombre(entreeHT.Text)
+		if err != nil || ht <= 0 {
+			return errors.New("montant HT invalide")
+		}
+		deduction, err := lireNombre(strings.TrimSuffix(strings.TrimSpace(entreeDeduction.Text), "%"))
+		if err != nil || deduction < 0 || deduction > 100 {
+			return errors.New("droit à déduction invalide (0 à 100 %)")
+		}
+		date, err := lireDate(entreeDate.Text)
+		if err != nil {
+			return err
+		}
+		for _, compte := range []string{entreeCharge.Text, entreeFournisseur.Text} {
+			if _, err := validerCompte(strings.TrimSpace(compte)); err != nil {
+				return err
+			}
+		}
+		taux, _ := lireNombre(selectTaux.Selected)
+		ht = arrondir(ht, decimales)
+
+		r := calculerAutoliquidation(ht, taux, deduction, decimales)
+		compteDeductible := c.reglages.Comptes.TVADeductible
+		if checkImmobilisation.Checked {
+			compteDeductible = c.reglages.Comptes.TVADeductibleImmo
+		}
+		e := ecritureAutoliquidation(op, ht, r, c.reglages.Comptes.JournalAchats,
+			strings.TrimSpace(entreeCharge.Text), compteDeductible, strings.TrimSpace(entreeFournisseur.Text))
+		e.date = date
+		e.piece = strings.TrimSpace(entreePiece.Text)
+		courante = &e
+
+		f := func(n float64) string { return formaterDecimales(n, decimales) }
+		resultat.SetText(fmt.Sprintf("TVA due (%s) : %s   TVA déductible (%s) : %s   Effet net : %s",
+			op.compteDue, f(r.due), compteDeductible, f(r.deductible), f(r.net)))
+		t.lignes = nil
+		for _, l := range e.lignes {
+			t.lignes = append(t.lignes, []string{e.journal, formaterDate(e.date), e.piece, l.compte,
+				c.pcg().libelle(l.compte), l.libelle, formaterMontantEcriture(l.debit, decimales),
+				formaterMontantEcriture(l.credit, decimales)})
+		}
+		ajusterColonnes(table, t)
+
+		if versHistorique {
+			operation := fmt.Sprintf("Autoliquidation %s %s %% sur %s", op.court, formaterCle(taux), f(ht))
+			c.ajouterHistorique(fmt.Sprintf("%s : TVA due (%s) = %s", operation, op.compteDue, f(r.due)))
+			c.ajouterHistorique(fmt.Sprintf("%s : TVA déductible %s %% (%s) = %s", operation,
+				formaterCle(deduction), compteDeductible, f(r.deductible)))
+			c.afficherResultat(operation+" : effet net", r.net)
+		}
+		return nil
+	}
+
+	btnCalculer := widget.NewButton("Calculer", func() {
+		if err := calculer(true); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	btnCalculer.Importance = widget.HighImportance
+	btnFEC := widget.NewButton("Exporter FEC", func() {
+		if err := calculer(false); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		numero := courante.piece
+		if numero == "" {
+			numero = "1"
+		}
+		nom, err := c.nomFichierFEC(courante.date)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		c.exporterFichier(nom, ecrireFEC(courante.lignesFEC(numero, c.pcg())), w)
+	})
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Opération", selectOperation),
+		widget.NewFormItem("Montant HT", entreeHT),
+		widget.NewFormItem("Taux de TVA", selectTaux),
+		widget.NewFormItem("Droit à déduction %", entreeDeduction),
+		widget.NewFormItem("Date", entreeDate),
+		widget.NewFormItem("Pièce", entreePiece),
+		widget.NewFormItem("Compte de charge", champCharge),
+		widget.NewFormItem("Fournisseur", champFournisseur),
+		widget.NewFormItem("", checkImmobilisation),
+	)
+	haut := container.NewVBox(formulaire, btnCalculer, resultat)
+	bas := container.NewVBox(c.boutonsExport(t, w), btnFEC)
+	w.SetContent(container.NewPadded(container.NewBorder(haut, bas, nil, nil, table)))
+	w.Show()
+}
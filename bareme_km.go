@@ -0,0 +1,214 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// BARÈME KILOMÉTRIQUE (INDEMNITÉS KILOMÉTRIQUES)
+// ========================================
+
+const fichierBaremeKm = "bareme_kilometrique.csv"
+
+//go:embed donnees/bareme_kilometrique.csv
+var baremeKmDefaut []byte
+
+// Majoration des véhicules 100 % électriques
+const MajorationElectrique = 20.0
+
+var TypesVehicule = []string{"voiture", "moto", "cyclomoteur"}
+
+// Une tranche du barème : indemnité = distance x coefficient + forfait
+type trancheKm struct {
+	annee       int
+	vehicule    string
+	cvMin       int
+	cvMax       int // 0 = sans limite
+	kmMin       float64
+	kmMax       float64 // 0 = sans limite
+	coefficient float64
+	forfait     float64
+}
+
+type baremeKm []trancheKm
+
+func lireBaremeKm(donnees []byte) (baremeKm, error) {
+	var bareme baremeKm
+	for i, champs := range lireLignesCSV(donnees) {
+		erreur := func(quoi string) error {
+			return fmt.Errorf("%s, ligne %d : %s", fichierBaremeKm, i+1, quoi)
+		}
+		if len(champs) < 8 {
+			return nil, erreur("8 colonnes attendues")
+		}
+		var tr trancheKm
+		var err error
+		if tr.annee, err = strconv.Atoi(champs[0]); err != nil {
+			return nil, erreur("année invalide")
+		}
+		tr.vehicule = strings.ToLower(champs[1])
+		if tr.cvMin, err = strconv.Atoi(champs[2]); err != nil {
+			return nil, erreur("puissance invalide")
+		}
+		if champs[3] != "" {
+			if tr.cvMax, err = strconv.Atoi(champs[3]); err != nil {
+				return nil, erreur("puissance invalide")
+			}
+		}
+		if tr.kmMin, err = lireNombre(champs[4]); err != nil {
+			return nil, erreur("distance invalide")
+		}
+		if champs[5] != "" {
+			if tr.kmMax, err = lireNombre(champs[5]); err != nil || tr.kmMax < tr.kmMin {
+				return nil, erreur("distance invalide")
+			}
+		}
+		if tr.coefficient, err = lireNombre(champs[6]); err != nil {
+			return nil, erreur("coefficient invalide")
+		}
+		if tr.forfait, err = lireNombre(champs[7]); err != nil {
+			return nil, erreur("forfait invalide")
+		}
+		bareme = append(bareme, tr)
+	}
+	if len(bareme) == 0 {
+		return nil, fmt.Errorf("%s : barème vide", fichierBaremeKm)
+	}
+	sort.SliceStable(bareme, func(i, j int) bool { return bareme[i].kmMin < bareme[j].kmMin })
+	// Tranches d'une même catégorie de véhicule : pas de chevauchement
+	type categorie struct {
+		annee        int
+		vehicule     string
+		cvMin, cvMax int
+	}
+	precedentes := make(map[categorie]trancheKm)
+	for _, tr := range bareme {
+		cat := categorie{tr.annee, tr.vehicule, tr.cvMin, tr.cvMax}
+		if p, ok := precedentes[cat]; ok && (p.kmMax == 0 || p.kmMax >= tr.kmMin) {
+			return nil, fmt.Errorf("%s : tranches %d %s qui se chevauchent à %s km",
+				fichierBaremeKm, tr.annee, tr.vehicule, formaterCle(tr.kmMin))
+		}
+		precedentes[cat] = tr
+	}
+	return bareme, nil
+}
+
+func chargerBaremeKm() (baremeKm, error) {
+	donnees, err := lireTableConfig(fichierBaremeKm, baremeKmDefaut)
+	if err != nil {
+		return nil, err
+	}
+	return lireBaremeKm(donnees)
+}
+
+// Années disponibles, la plus récente en premier
+func (b baremeKm) annees() []string {
+	vues := make(map[int]bool)
+	var annees []int
+	for _, tr := range b {
+		if !vues[tr.annee] {
+			vues[tr.annee] = true
+			annees = append(annees, tr.annee)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(annees)))
+	var libelles []string
+	for _, a := range annees {
+		libelles = append(libelles, strconv.Itoa(a))
+	}
+	return libelles
+}
+
+// Tranche applicable (distance arrondie au km supérieur entre km_min et km_max)
+func (b baremeKm) tranche(annee int, vehicule string, cv int, distance float64) (trancheKm, error) {
+	km := math.Ceil(distance)
+	for _, tr := range b {
+		if tr.annee != annee || tr.vehicule != vehicule || cv < tr.cvMin || (tr.cvMax > 0 && cv > tr.cvMax) {
+			continue
+		}
+		if tr.kmMin <= km && (tr.kmMax == 0 || km <= tr.kmMax) {
+			return tr, nil
+		}
+	}
+	return trancheKm{}, fmt.Errorf("pas de tranche %d pour %s de %d CV à %s km", annee, vehicule, cv, formaterCle(km))
+}
+
+// Indemnité et formule appliquée
+func (tr trancheKm) indemnite(distance float64, electrique bool) (float64, string) {
+	montant := distance*tr.coefficient + tr.forfait
+	formule := fmt.Sprintf("%s km x %s", formaterCle(distance), formaterCle(tr.coefficient))
+	if tr.forfait != 0 {
+		formule = fmt.Sprintf("(%s) + %s", formule, formaterCle(tr.forfait))
+	}
+	if electrique {
+		montant *= 1 + MajorationElectrique/100
+		formule = fmt.Sprintf("[%s] x %s (électrique)", formule, formaterCle(1+MajorationElectrique/100))
+	}
+	return arrondir(montant, 2), formule
+}
+
+// ========================================
+// BOÎTE DE DIALOGUE BARÈME KILOMÉTRIQUE
+// ========================================
+
+func (c *Calculatrice) dialogueBaremeKm() {
+	bareme, err := chargerBaremeKm()
+	if err != nil {
+		dialog.ShowError(err, c.fenetre)
+		return
+	}
+
+	selectAnnee := widget.NewSelect(bareme.annees(), nil)
+	selectAnnee.SetSelectedIndex(0)
+	selectVehicule := widget.NewSelect(TypesVehicule, nil)
+	selectVehicule.SetSelected("voiture")
+	entreeCV := widget.NewEntry()
+	entreeCV.SetText("5")
+	entreeDistance := widget.NewEntry()
+	if v := c.obtenirValeurCourante(); v > 0 {
+		entreeDistance.SetText(c.formaterNombre(c.valeurCourante))
+	}
+	checkElectrique := widget.NewCheck(fmt.Sprintf("Véhicule électrique (+%s %%)", formaterCle(MajorationElectrique)), nil)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Année", selectAnnee),
+		widget.NewFormItem("Véhicule", selectVehicule),
+		widget.NewFormItem("Puissance (CV)", entreeCV),
+		widget.NewFormItem("Distance (km)", entreeDistance),
+		widget.NewFormItem("", checkElectrique),
+	}
+
+	dialog.ShowForm("Barème kilométrique", "Calculer", "Annuler", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		annee, _ := strconv.Atoi(selectAnnee.Selected)
+		cv, err := strconv.Atoi(strings.TrimSpace(entreeCV.Text))
+		if err != nil || cv < 1 {
+			dialog.ShowError(errors.New("puissance fiscale invalide"), c.fenetre)
+			return
+		}
+		distance, err := lireNombre(entreeDistance.Text)
+		if err != nil || distance <= 0 {
+			dialog.ShowError(errors.New("distance invalide"), c.fenetre)
+			return
+		}
+
+		tr, err := bareme.tranche(annee, selectVehicule.Selected, cv, distance)
+		if err != nil {
+			dialog.ShowError(err, c.fenetre)
+			return
+		}
+		montant, formule := tr.indemnite(distance, checkElectrique.Checked)
+		c.afficherResultat(fmt.Sprintf("IK %d %s %d CV : %s", annee, selectVehicule.Selected, cv, formule), montant)
+	}, c.fenetre)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// BROUILLARD D'ÉCRITURE (ÉQUILIBRE DÉBIT / CRÉDIT)
+// ========================================
+
+// Contrôle un numéro de compte selon la structure du PCG : classe 1 à 8,
+// au moins 3 chiffres, suffixe alphanumérique admis (compte auxiliaire).
+// Retourne le libellé de la classe.
+func validerCompte(compte string) (string, error) {
+	if compte == "" {
+		return "", errors.New("numéro de compte manquant")
+	}
+	classe := compte[:1]
+	if classe < "1" || classe > "8" {
+		return "", fmt.Errorf("compte %s : la classe doit être comprise entre 1 et 8", compte)
+	}
+	chiffres := len(compte) - len(strings.TrimLeft(compte, "0123456789"))
+	if chiffres < 3 {
+		return "", fmt.Errorf("compte %s : au moins 3 chiffres attendus", compte)
+	}
+	for _, r := range compte[chiffres:] {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return "", fmt.Errorf("compte %s : caractère %q non admis", compte, r)
+		}
+	}
+	return ClassesPCG[classe], nil
+}
+
+// Montant qui solde l'écriture, au débit ou au crédit (compte à compléter)
+func ligneEquilibre(e ecriture, decimales int) (ligneEcriture, bool) {
+	debit, credit := e.totaux(decimales)
+	ecart := arrondir(debit-credit, decimales)
+	if ecart == 0 {
+		return ligneEcriture{}, false
+	}
+	return nouvelleLigneEcriture("", "", ecart, false), true
+}
+
+// ========================================
+// FENÊTRE BROUILLARD
+// ========================================
+
+func (c *Calculatrice) fenetreBrouillard() {
+	w := c.nouvelleFenetre("Brouillard d'écriture", 850, 650)
+	decimales := c.reglages.decimales()
+
+	entreeCompte, champCompte := c.champCompte(w)
+	entreeCompte.SetPlaceHolder("Compte")
+	entreeLibelle := widget.NewEntry()
+	entreeLibelle.SetPlaceHolder("Libellé")
+	entreeDebit := widget.NewEntry()
+	entreeDebit.SetPlaceHolder("Débit")
+	entreeCredit := widget.NewEntry()
+	entreeCredit.SetPlaceHolder("Crédit")
+
+	var e ecriture
+	t := &tableau{
+		titre:   "Brouillard",
+		entetes: []string{"N°", "Compte", "Intitulé", "Libellé", "Débit", "Crédit"},
+	}
+	table := nouveauTableauWidget(t)
+	totaux := widget.NewLabel("")
+	totaux.TextStyle = fyne.TextStyle{Bold: true}
+	proposition := widget.NewLabel("")
+	proposition.TextStyle = fyne.TextStyle{Italic: true}
+
+	// Montant saisi, vide = 0
+	montantSaisi := func(entree *widget.Entry) (float64, error) {
+		if strings.TrimSpace(entree.Text) == "" {
+			return 0, nil
+		}
+		return lireNombre(entree.Text)
+	}
+
+	// Totaux et écart, y compris la ligne en cours de saisie
+	majTotaux := func() {
+		debit, credit := e.totaux(decimales)
+		texte := fmt.Sprintf("Débit %s   Crédit %s   Écart %s", formaterDecimales(debit, decimales),
+			formaterDecimales(credit, decimales), formaterDecimales(debit-credit, decimales))
+		d, errD := montantSaisi(entreeDebit)
+		cr, errC := montantSaisi(entreeCredit)
+		if errD == nil && errC == nil && (d != 0 || cr != 0) {
+			texte += fmt.Sprintf("   (avec la ligne en cours : %s)", formaterDecimales(debit+d-credit-cr, decimales))
+		}
+		totaux.SetText(texte)
+		if arrondir(debit-credit, decimales) == 0 {
+			totaux.Importance = widget.SuccessImportance
+		} else {
+			totaux.Importance = widget.DangerImportance
+		}
+		totaux.Refresh()
+	}
+	entreeDebit.OnChanged = func(string) { majTotaux() }
+	entreeCredit.OnChanged = func(string) { majTotaux() }
+
+	majTableau := func() {
+		t.lignes = nil
+		for i, l := range e.lignes {
+			t.lignes = append(t.lignes, []string{strconv.Itoa(i + 1), l.compte, c.pcg().libelle(l.compte), l.libelle,
+				formaterMontantEcriture(l.debit, decimales), formaterMontantEcriture(l.credit, decimales)})
+		}
+		ajusterColonnes(table, t)
+
+		// La ligne suivante propose le montant qui solde l'écriture
+		entreeDebit.SetText("")
+		entreeCredit.SetText("")
+		if l, ok := ligneEquilibre(e, decimales); ok {
+			if l.debit != 0 {
+				entreeDebit.SetText(formaterDecimales(l.debit, decimales))
+				proposition.SetText(fmt.Sprintf("Ligne d'équilibre proposée : %s au débit", formaterDecimales(l.debit, decimales)))
+			} else {
+				entreeCredit.SetText(formaterDecimales(l.credit, decimales))
+				proposition.SetText(fmt.Sprintf("Ligne d'équilibre proposée : %s au crédit", formaterDecimales(l.credit, decimales)))
+			}
+		} else if len(e.lignes) > 0 {
+			proposition.SetText("Écriture équilibrée")
+		} else {
+			proposition.SetText("")
+		}
+		majTotaux()
+	}
+
+	ajouter := func() {
+		compte := strings.TrimSpace(entreeCompte.Text)
+		if _, err := validerCompte(compte); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		debit, err := montantSaisi(entreeDebit)
+		if err != nil {
+			dialog.ShowError(errors.New("montant au débit invalide"), w)
+			return
+		}
+		credit, err := montantSaisi(entreeCredit)
+		if err != nil {
+			dialog.ShowError(errors.New("montant au crédit invalide"), w)
+			return
+		}
+		if (debit == 0) == (credit == 0) {
+			dialog.ShowError(errors.New("saisissez un montant au débit ou au crédit"), w)
+			return
+		}
+		libelle := strings.TrimSpace(entreeLibelle.Text)
+		if debit != 0 {
+			e.lignes = append(e.lignes, nouvelleLigneEcriture(compte, libelle, arrondir(debit, decimales), true))
+		} else {
+			e.lignes = append(e.lignes, nouvelleLigneEcriture(compte, libelle, arrondir(credit, decimales), false))
+		}
+		majTableau()
+		entreeCompte.SetText("")
+		w.Canvas().Focus(entreeCompte)
+	}
+	entreeDebit.OnSubmitted = func(string) { ajouter() }
+	entreeCredit.OnSubmitted = func(string) { ajouter() }
+
+	btnAjouter := widget.NewButton("Ajouter", ajouter)
+	btnAjouter.Importance = widget.HighImportance
+	btnSupprimer := widget.NewButton("Supprimer la dernière", func() {
+		if len(e.lignes) > 0 {
+			e.lignes = e.lignes[:len(e.lignes)-1]
+			majTableau()
+		}
+	})
+	btnVider := widget.NewButton("Vider", func() {
+		e.lignes = nil
+		majTableau()
+	})
+	btnEcart := widget.NewButton("Écart > calculatrice", func() {
+		debit, credit := e.totaux(decimales)
+		c.afficherResultat(fmt.Sprintf("Brouillard (%d lignes) : %s - %s", len(e.lignes),
+			formaterDecimales(debit, decimales), formaterDecimales(credit, decimales)), debit-credit)
+	})
+
+	saisie := container.NewBorder(nil, nil, nil, btnAjouter,
+		container.NewGridWithColumns(4, champCompte, entreeLibelle, entreeDebit, entreeCredit))
+	actions := container.NewGridWithColumns(3, btnSupprimer, btnVider, btnEcart)
+	haut := container.NewVBox(saisie, proposition, totaux, actions)
+	majTableau()
+	w.SetContent(container.NewPadded(container.NewBorder(haut, c.boutonsExport(t, w), nil, nil, table)))
+	w.Show()
+}
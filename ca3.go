@@ -0,0 +1,298 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// DÉCLARATION DE TVA (FEUILLE DE CALCUL CA3)
+// ========================================
+
+// Ligne de TVA brute de la CA3 selon le taux (les autres taux en ligne 14)
+var LignesTauxCA3 = map[float64]string{20: "08", 5.5: "09", 10: "9B"}
+
+// Données de la feuille, en montants saisis
+type feuilleCA3 struct {
+	bases           map[float64]float64 // Bases HT des opérations imposables, par taux
+	aReverser       float64             // 15 : TVA antérieurement déduite à reverser
+	immobilisations float64             // 19 : TVA déductible sur immobilisations
+	autresBiens     float64             // 20 : TVA déductible sur autres biens et services
+	autreADeduire   float64             // 21 : autre TVA à déduire (régularisations)
+	report          float64             // 22 : crédit reporté de la déclaration précédente
+	remboursement   float64             // 26 : remboursement de crédit demandé
+	assimilees      float64             // 29 : taxes assimilées
+}
+
+type ligneCA3 struct {
+	code, libelle string
+	base, taxe    float64
+	avecBase      bool
+}
+
+// Calcule les lignes principales de la CA3. À l'euro près, les bases sont
+// arrondies avant le calcul de la taxe, comme sur la déclaration.
+func calculerCA3(f feuilleCA3, taux []float64, decimales int) (lignes []ligneCA3, netDue, credit float64) {
+	ajouter := func(code, libelle string, taxe float64) {
+		lignes = append(lignes, ligneCA3{code: code, libelle: libelle, taxe: arrondir(taxe, decimales)})
+	}
+
+	var totalBases, brute float64
+	for _, t := range taux {
+		base := arrondir(f.bases[t], decimales)
+		if base == 0 {
+			continue
+		}
+		code, ok := LignesTauxCA3[t]
+		if !ok {
+			code = "14"
+		}
+		taxe := arrondir(base*t/100, decimales)
+		lignes = append(lignes, ligneCA3{code, fmt.Sprintf("TVA brute à %s %%", formaterCle(t)), base, taxe, true})
+		totalBases += base
+		brute += taxe
+	}
+	lignes = append([]ligneCA3{{code: "01", libelle: "Opérations imposables (HT)", base: totalBases, avecBase: true}}, lignes...)
+	if f.aReverser != 0 {
+		ajouter("15", "TVA antérieurement déduite à reverser", f.aReverser)
+		brute += arrondir(f.aReverser, decimales)
+	}
+	ajouter("16", "Total de la TVA brute due", brute)
+
+	deductible := arrondir(f.immobilisations, decimales) + arrondir(f.autresBiens, decimales) +
+		arrondir(f.autreADeduire, decimales) + arrondir(f.report, decimales)
+	ajouter("19", "Biens constituant des immobilisations", f.immobilisations)
+	ajouter("20", "Autres biens et services", f.autresBiens)
+	if f.autreADeduire != 0 {
+		ajouter("21", "Autre TVA à déduire", f.autreADeduire)
+	}
+	if f.report != 0 {
+		ajouter("22", "Report du crédit de la déclaration précédente", f.report)
+	}
+	ajouter("23", "Total de la TVA déductible", deductible)
+
+	solde := arrondir(brute-deductible, decimales)
+	if solde < 0 {
+		credit = -solde
+		ajouter("25", "Crédit de TVA", credit)
+		remboursement := arrondir(f.remboursement, decimales)
+		if remboursement > credit {
+			remboursement = credit
+		}
+		if remboursement != 0 {
+			ajouter("26", "Remboursement demandé", remboursement)
+		}
+		ajouter("27", "Crédit à reporter", credit-remboursement)
+	} else {
+		netDue = solde
+		ajouter("28", "TVA nette due", netDue)
+	}
+	if f.assimilees != 0 {
+		ajouter("29", "Taxes assimilées", f.assimilees)
+	}
+	ajouter("32", "Total à payer", netDue+arrondir(f.assimilees, decimales))
+	return lignes, netDue, credit
+}
+
+// Entrées produites par calculerTVA : "TVA 20.0% de 1500 = 300", avec le
+// détail de l'arrondi espèces éventuel entre crochets
+var motifCalculTVA = regexp.MustCompile(`^TVA ([0-9]+(?:[.,][0-9]+)?)% de ([^=\[]+?)(?: \[[^\]]*\])? = (.+)$`)
+
+// Base, taux et TVA d'une entrée d'historique de calcul de TVA. La TVA est
+// recalculée (base × taux) : le montant affiché peut être arrondi.
+func lireCalculTVA(entree string) (base, taux, tva float64, ok bool) {
+	m := motifCalculTVA.FindStringSubmatch(entree)
+	if m == nil {
+		return 0, 0, 0, false
+	}
+	var err1, err2 error
+	taux, err1 = lireNombre(m[1])
+	base, err2 = lireNombre(m[2])
+	return base, taux, base * taux / 100, err1 == nil && err2 == nil
+}
+
+// ========================================
+// FENÊTRE DÉCLARATION CA3
+// ========================================
+
+const (
+	ImportCollectee       = "Bases de TVA collectée"
+	ImportAutresBiens     = "TVA déductible (autres biens et services)"
+	ImportImmobilisations = "TVA déductible (immobilisations)"
+)
+
+func (c *Calculatrice) fenetreCA3() {
+	w := c.nouvelleFenetre("Déclaration de TVA (CA3)", 900, 750)
+
+	// Une base par taux des réglages
+	taux := c.reglages.TauxTVA
+	entreesBases := make(map[float64]*widget.Entry)
+	formBases := widget.NewForm()
+	for _, t := range taux {
+		e := widget.NewEntry()
+		e.SetPlaceHolder("Base HT")
+		entreesBases[t] = e
+		formBases.Append(fmt.Sprintf("Base à %s %%", formaterCle(t)), e)
+	}
+
+	nouvelleEntree := func() *widget.Entry {
+		e := widget.NewEntry()
+		e.SetPlaceHolder("0")
+		return e
+	}
+	entreeReverser := nouvelleEntree()
+	entreeImmos := nouvelleEntree()
+	entreeAutres := nouvelleEntree()
+	entreeAutreADeduire := nouvelleEntree()
+	entreeReport := nouvelleEntree()
+	entreeRemboursement := nouvelleEntree()
+	entreeAssimilees := nouvelleEntree()
+	formDeductible := widget.NewForm(
+		widget.NewFormItem("15 TVA à reverser", entreeReverser),
+		widget.NewFormItem("19 Immobilisations", entreeImmos),
+		widget.NewFormItem("20 Autres biens et services", entreeAutres),
+		widget.NewFormItem("21 Autre TVA à déduire", entreeAutreADeduire),
+		widget.NewFormItem("22 Crédit reporté", entreeReport),
+		widget.NewFormItem("26 Remboursement demandé", entreeRemboursement),
+		widget.NewFormItem("29 Taxes assimilées", entreeAssimilees),
+	)
+	checkEuro := widget.NewCheck("Montants arrondis à l'euro (déclaration)", nil)
+	checkEuro.SetChecked(true)
+
+	// Reprise des calculs de TVA de l'historique
+	selectImport := widget.NewSelect([]string{ImportCollectee, ImportAutresBiens, ImportImmobilisations}, nil)
+	selectImport.SetSelected(ImportCollectee)
+	// Les champs repris sont remplacés par les totaux de l'historique :
+	// reprendre deux fois ne double pas les montants
+	btnImporter := widget.NewButton("Reprendre l'historique", func() {
+		bases := make(map[float64]float64)
+		var totalTVA float64
+		n := 0
+		for _, entree := range c.listeHistorique {
+			base, t, tva, ok := lireCalculTVA(entree)
+			if !ok {
+				continue
+			}
+			if selectImport.Selected == ImportCollectee {
+				if _, connu := entreesBases[t]; !connu {
+					continue
+				}
+				bases[t] += base
+			}
+			totalTVA += tva
+			n++
+		}
+		if n == 0 {
+			dialog.ShowInformation("Historique", "Aucun calcul de TVA repris.", w)
+			return
+		}
+		switch selectImport.Selected {
+		case ImportCollectee:
+			for t, e := range entreesBases {
+				e.SetText("")
+				if base, ok := bases[t]; ok {
+					e.SetText(formaterDecimales(base, 2))
+				}
+			}
+		case ImportAutresBiens:
+			entreeAutres.SetText(formaterDecimales(totalTVA, 2))
+		case ImportImmobilisations:
+			entreeImmos.SetText(formaterDecimales(totalTVA, 2))
+		}
+		dialog.ShowInformation("Historique", fmt.Sprintf("%d calcul(s) de TVA repris.", n), w)
+	})
+
+	t := &tableau{titre: "Declaration_CA3", entetes: []string{"Ligne", "Libellé", "Base HT", "Taxe"}}
+	table := nouveauTableauWidget(t)
+	resume := widget.NewLabel("")
+	resume.TextStyle = fyne.TextStyle{Bold: true}
+
+	calculer := func() {
+		lire := func(e *widget.Entry, nom string) (float64, error) {
+			if strings.TrimSpace(e.Text) == "" {
+				return 0, nil
+			}
+			n, err := lireNombre(e.Text)
+			if err != nil {
+				return 0, fmt.Errorf("%s : montant invalide", nom)
+			}
+			return n, nil
+		}
+		f := feuilleCA3{bases: make(map[float64]float64)}
+		var err error
+		for _, tx := range taux {
+			if f.bases[tx], err = lire(entreesBases[tx], "base à "+formaterCle(tx)+" %"); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+		}
+		for _, champ := range []struct {
+			entree *widget.Entry
+			valeur *float64
+			nom    string
+		}{
+			{entreeReverser, &f.aReverser, "ligne 15"},
+			{entreeImmos, &f.immobilisations, "ligne 19"},
+			{entreeAutres, &f.autresBiens, "ligne 20"},
+			{entreeAutreADeduire, &f.autreADeduire, "ligne 21"},
+			{entreeReport, &f.report, "ligne 22"},
+			{entreeRemboursement, &f.remboursement, "ligne 26"},
+			{entreeAssimilees, &f.assimilees, "ligne 29"},
+		} {
+			if *champ.valeur, err = lire(champ.entree, champ.nom); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+		}
+		if f.remboursement < 0 {
+			dialog.ShowError(errors.New("ligne 26 : montant négatif"), w)
+			return
+		}
+
+		decimales := c.reglages.decimales()
+		if checkEuro.Checked {
+			decimales = 0
+		}
+		lignes, netDue, credit := calculerCA3(f, taux, decimales)
+		t.lignes = nil
+		for _, l := range lignes {
+			base := ""
+			if l.avecBase {
+				base = formaterDecimales(l.base, decimales)
+			}
+			taxe := ""
+			if l.code != "01" {
+				taxe = formaterDecimales(l.taxe, decimales)
+			}
+			t.lignes = append(t.lignes, []string{l.code, l.libelle, base, taxe})
+		}
+		ajusterColonnes(table, t)
+
+		if credit > 0 {
+			resume.SetText(fmt.Sprintf("Crédit de TVA : %s", formaterDecimales(credit, decimales)))
+			c.afficherResultat("CA3 : crédit de TVA", credit)
+		} else {
+			resume.SetText(fmt.Sprintf("TVA nette due : %s", formaterDecimales(netDue, decimales)))
+			c.afficherResultat("CA3 : TVA nette due", netDue)
+		}
+	}
+
+	btnCalculer := widget.NewButton("Calculer la déclaration", calculer)
+	btnCalculer.Importance = widget.HighImportance
+
+	reprise := container.NewBorder(nil, nil, nil, btnImporter, selectImport)
+	saisie := container.NewGridWithColumns(2,
+		container.NewVBox(widget.NewLabelWithStyle("TVA collectée", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), formBases, checkEuro),
+		container.NewVBox(widget.NewLabelWithStyle("TVA déductible et régularisations", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), formDeductible),
+	)
+	haut := container.NewVBox(saisie, reprise, btnCalculer, resume)
+	w.SetContent(container.NewPadded(container.NewBorder(haut, c.boutonsExport(t, w), nil, nil, table)))
+	w.Show()
+}
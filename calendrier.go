@@ -0,0 +1,235 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// FENÊTRE DATES ET ÉCHÉANCES
+// ========================================
+
+// Dans l'ordre des constantes TermeNet, TermeFinDeMois, TermeFinDeMoisNet
+var ModesTerme = []string{"Net (date + X jours)", "X jours fin de mois", "Fin de mois + X jours"}
+
+// Nombre maximal de jours ouvrés ajoutés ou retirés (environ 14 ans)
+const MaxJoursOuvres = 3650
+
+var JoursSemaine = []string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"}
+
+func jourSemaine(d time.Time) string {
+	return JoursSemaine[d.Weekday()]
+}
+
+func (c *Calculatrice) fenetreDates() {
+	w := c.nouvelleFenetre("Dates et échéances", 750, 600)
+
+	checkAlsace := widget.NewCheck("Alsace-Moselle (Vendredi saint, 26 décembre)", nil)
+	calendrier := func() *calendrierFeries {
+		return nouveauCalendrier(checkAlsace.Checked)
+	}
+
+	onglets := container.NewAppTabs(
+		container.NewTabItem("Échéance", c.ongletEcheance(w, calendrier)),
+		container.NewTabItem("Écart entre dates", c.ongletEcart(w, calendrier)),
+		container.NewTabItem("Jours ouvrés", c.ongletJoursOuvres(w, calendrier)),
+		container.NewTabItem("Jours fériés", c.ongletFeries(w, calendrier)),
+	)
+
+	w.SetContent(container.NewPadded(container.NewBorder(nil, checkAlsace, nil, nil, onglets)))
+	w.Show()
+}
+
+// Date de facture + condition de paiement, avec contrôle des plafonds LME
+func (c *Calculatrice) ongletEcheance(w fyne.Window, calendrier func() *calendrierFeries) fyne.CanvasObject {
+	entreeFacture := widget.NewEntry()
+	entreeFacture.SetText(formaterDate(aujourdhui()))
+	selectMode := widget.NewSelect(ModesTerme, nil)
+	selectMode.SetSelected(ModesTerme[1])
+	entreeJours := widget.NewEntry()
+	entreeJours.SetText("45")
+
+	resultat := widget.NewLabel("")
+	resultat.TextStyle = fyne.TextStyle{Bold: true}
+	detail := widget.NewLabel("")
+	alerte := widget.NewLabel("")
+	alerte.Importance = widget.DangerImportance
+	alerte.Wrapping = fyne.TextWrapWord
+
+	calculer := func() {
+		facture, err := lireDate(entreeFacture.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		jours, err := strconv.Atoi(strings.TrimSpace(entreeJours.Text))
+		if err != nil || jours < 0 {
+			dialog.ShowError(errors.New("nombre de jours invalide"), w)
+			return
+		}
+
+		condition := nouvelleCondition(selectMode.SelectedIndex(), jours)
+		echeance := condition.echeance(facture)
+		resultat.SetText(fmt.Sprintf("Échéance : %s %s", jourSemaine(echeance), formaterDate(echeance)))
+
+		soixante, fdm := limitesLME(facture)
+		lignes := []string{
+			fmt.Sprintf("Délai : %d jours calendaires", joursEntre(facture, echeance)),
+			fmt.Sprintf("Plafond 60 jours date de facture : %s", formaterDate(soixante)),
+			fmt.Sprintf("Plafond 45 jours fin de mois : %s", formaterDate(fdm)),
+		}
+		if nom, ferie := calendrier().ferie(echeance); ferie {
+			lignes = append(lignes, fmt.Sprintf("L'échéance tombe un jour férié (%s)", nom))
+		} else if !calendrier().ouvre(echeance) {
+			lignes = append(lignes, "L'échéance tombe un week-end")
+		}
+		detail.SetText(strings.Join(lignes, "\n"))
+
+		if depasseLME(facture, echeance) {
+			alerte.SetText(fmt.Sprintf("Délai supérieur aux plafonds légaux (art. L441-10 C. com.) : %s au-delà du %s",
+				formaterDate(echeance), formaterDate(maxDate(soixante, fdm))))
+		} else {
+			alerte.SetText("")
+		}
+
+		c.ajouterHistorique(fmt.Sprintf("Facture du %s, %s = %s",
+			formaterDate(facture), strings.ToLower(condition.libelle), formaterDate(echeance)))
+	}
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Date de facture", entreeFacture),
+		widget.NewFormItem("Condition", selectMode),
+		widget.NewFormItem("Jours (X)", entreeJours),
+	)
+	btn := widget.NewButton("Calculer l'échéance", calculer)
+	btn.Importance = widget.HighImportance
+	return container.NewVBox(formulaire, btn, resultat, detail, alerte)
+}
+
+// Jours calendaires, ouvrés et ouvrables entre deux dates
+func (c *Calculatrice) ongletEcart(w fyne.Window, calendrier func() *calendrierFeries) fyne.CanvasObject {
+	entreeDebut := widget.NewEntry()
+	entreeDebut.SetText(formaterDate(aujourdhui()))
+	entreeFin := widget.NewEntry()
+	entreeFin.SetText(formaterDate(finDeMois(aujourdhui())))
+	resultat := widget.NewLabel("")
+
+	calculer := func() {
+		debut, err := lireDate(entreeDebut.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		fin, err := lireDate(entreeFin.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		cal := calendrier()
+		calendaires := joursEntre(debut, fin)
+		ouvres := cal.compterJours(debut, fin, cal.ouvre)
+		ouvrables := cal.compterJours(debut, fin, cal.ouvrable)
+		resultat.SetText(fmt.Sprintf("Jours calendaires : %d\nJours ouvrés (lun-ven) : %d\nJours ouvrables (lun-sam) : %d",
+			calendaires, ouvres, ouvrables))
+
+		periode := fmt.Sprintf("Du %s au %s", formaterDate(debut), formaterDate(fin))
+		c.ajouterHistorique(fmt.Sprintf("%s (jours ouvrés) = %d", periode, ouvres))
+		c.afficherResultat(fmt.Sprintf("%s (jours calendaires)", periode), float64(calendaires))
+	}
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Du", entreeDebut),
+		widget.NewFormItem("Au", entreeFin),
+	)
+	btn := widget.NewButton("Compter les jours", calculer)
+	btn.Importance = widget.HighImportance
+	aide := widget.NewLabel("Le premier jour n'est pas compté, le dernier l'est.")
+	aide.TextStyle = fyne.TextStyle{Italic: true}
+	return container.NewVBox(formulaire, btn, resultat, aide)
+}
+
+// Date + N jours ouvrés
+func (c *Calculatrice) ongletJoursOuvres(w fyne.Window, calendrier func() *calendrierFeries) fyne.CanvasObject {
+	entreeDate := widget.NewEntry()
+	entreeDate.SetText(formaterDate(aujourdhui()))
+	entreeJours := widget.NewEntry()
+	entreeJours.SetText("10")
+	resultat := widget.NewLabel("")
+	resultat.TextStyle = fyne.TextStyle{Bold: true}
+
+	calculer := func() {
+		d, err := lireDate(entreeDate.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(entreeJours.Text))
+		if err != nil || n < -MaxJoursOuvres || n > MaxJoursOuvres {
+			dialog.ShowError(fmt.Errorf("nombre de jours invalide (%d au plus)", MaxJoursOuvres), w)
+			return
+		}
+
+		arrivee := calendrier().ajouterJoursOuvres(d, n)
+		resultat.SetText(fmt.Sprintf("%s %s", jourSemaine(arrivee), formaterDate(arrivee)))
+		c.ajouterHistorique(fmt.Sprintf("%s + %d jours ouvrés = %s", formaterDate(d), n, formaterDate(arrivee)))
+	}
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Date", entreeDate),
+		widget.NewFormItem("Jours ouvrés", entreeJours),
+	)
+	btn := widget.NewButton("Calculer", calculer)
+	btn.Importance = widget.HighImportance
+	return container.NewVBox(formulaire, btn, resultat)
+}
+
+// Liste des jours fériés d'une année
+func (c *Calculatrice) ongletFeries(w fyne.Window, calendrier func() *calendrierFeries) fyne.CanvasObject {
+	entreeAnnee := widget.NewEntry()
+	entreeAnnee.SetText(strconv.Itoa(aujourdhui().Year()))
+
+	t := &tableau{titre: "Jours_feries", entetes: []string{"Date", "Jour", "Férié"}}
+	table := nouveauTableauWidget(t)
+
+	afficher := func() {
+		annee, err := strconv.Atoi(strings.TrimSpace(entreeAnnee.Text))
+		if err != nil || annee < 1583 {
+			dialog.ShowError(errors.New("année invalide"), w)
+			return
+		}
+		feries := joursFeries(annee, calendrier().alsaceMoselle)
+		var dates []time.Time
+		for d := range feries {
+			dates = append(dates, d)
+		}
+		sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+		t.lignes = nil
+		for _, d := range dates {
+			t.lignes = append(t.lignes, []string{formaterDate(d), jourSemaine(d), feries[d]})
+		}
+		ajusterColonnes(table, t)
+	}
+	afficher()
+
+	btn := widget.NewButton("Afficher", afficher)
+	haut := container.NewBorder(nil, nil, widget.NewLabel("Année"), btn, entreeAnnee)
+	return container.NewBorder(haut, c.boutonsExport(t, w), nil, nil, table)
+}
+
+func maxDate(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
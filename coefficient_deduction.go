@@ -0,0 +1,204 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// COEFFICIENT DE DÉDUCTION DE LA TVA
+// ========================================
+
+// Coefficient de déduction = assujettissement × taxation × admission
+// (art. 206 de l'annexe II du CGI). Le coefficient provisoire de l'année
+// est remplacé par le coefficient définitif, connu à la clôture : l'écart
+// donne une déduction complémentaire ou un reversement.
+
+type coefficientsDeduction struct {
+	assujettissement, taxation, admission float64 // Fractions de 0 à 1
+}
+
+func (k coefficientsDeduction) deduction() float64 {
+	return k.assujettissement * k.taxation * k.admission
+}
+
+// Chiffre d'affaires ouvrant droit à déduction / chiffre d'affaires total,
+// arrondi au pourcentage supérieur (82,1 % -> 83 %)
+func coefficientTaxation(caTaxable, caTotal float64) float64 {
+	if caTotal <= 0 {
+		return 1
+	}
+	// La tolérance évite d'arrondir 0,83 en 0,84 à cause du calcul flottant
+	return math.Min(1, math.Ceil(caTaxable/caTotal*100-1e-9)/100)
+}
+
+type regularisationDeduction struct {
+	provisoire, definitive float64 // TVA déduite avec chaque coefficient
+	ecart                  float64 // > 0 : complément, < 0 : reversement
+}
+
+func calculerRegularisation(tva, provisoire, definitif float64, decimales int) regularisationDeduction {
+	p := arrondir(tva*provisoire, decimales)
+	d := arrondir(tva*definitif, decimales)
+	return regularisationDeduction{p, d, arrondir(d-p, decimales)}
+}
+
+// ========================================
+// FENÊTRE COEFFICIENT DE DÉDUCTION
+// ========================================
+
+func (c *Calculatrice) fenetreCoefficientDeduction() {
+	w := c.nouvelleFenetre("Coefficient de déduction", 750, 700)
+
+	nouvelleEntree := func(texte, aide string) *widget.Entry {
+		e := widget.NewEntry()
+		e.SetText(texte)
+		e.SetPlaceHolder(aide)
+		return e
+	}
+	entreeAssujettissement := nouvelleEntree("100", "%")
+	entreeCATaxable := nouvelleEntree("", "CA ouvrant droit à déduction")
+	entreeCATotal := nouvelleEntree("", "CA total")
+	entreeAdmission := nouvelleEntree("100", "%")
+	entreeTVA := nouvelleEntree("", "TVA grevant les dépenses de l'année")
+	if v := c.obtenirValeurCourante(); v > 0 {
+		entreeTVA.SetText(c.formaterNombre(c.valeurCourante))
+	}
+	entreeProvisoire := nouvelleEntree("", "Coefficient de l'année précédente, %")
+
+	resultat := widget.NewLabel("")
+	resultat.TextStyle = fyne.TextStyle{Bold: true}
+	resultat.Wrapping = fyne.TextWrapWord
+	t := &tableau{titre: "Coefficient_deduction", entetes: []string{"Élément", "Valeur"}}
+	table := nouveauTableauWidget(t)
+
+	lirePourcentage := func(e *widget.Entry, nom string) (float64, error) {
+		n, err := lireNombre(strings.TrimSuffix(strings.TrimSpace(e.Text), "%"))
+		if err != nil || n < 0 || n > 100 {
+			return 0, fmt.Errorf("%s : pourcentage invalide (0 à 100)", nom)
+		}
+		return n / 100, nil
+	}
+	lireMontant := func(e *widget.Entry, nom string) (float64, error) {
+		if strings.TrimSpace(e.Text) == "" {
+			return 0, nil
+		}
+		n, err := lireNombre(e.Text)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%s : montant invalide", nom)
+		}
+		return n, nil
+	}
+
+	calculer := func() error {
+		var k coefficientsDeduction
+		var err error
+		if k.assujettissement, err = lirePourcentage(entreeAssujettissement, "assujettissement"); err != nil {
+			return err
+		}
+		if k.admission, err = lirePourcentage(entreeAdmission, "admission"); err != nil {
+			return err
+		}
+		caTaxable, err := lireMontant(entreeCATaxable, "CA ouvrant droit à déduction")
+		if err != nil {
+			return err
+		}
+		caTotal, err := lireMontant(entreeCATotal, "CA total")
+		if err != nil {
+			return err
+		}
+		if caTaxable > caTotal {
+			return errors.New("le CA ouvrant droit à déduction dépasse le CA total")
+		}
+		k.taxation = coefficientTaxation(caTaxable, caTotal)
+		tva, err := lireMontant(entreeTVA, "TVA grevant les dépenses")
+		if err != nil {
+			return err
+		}
+		provisoire := -1.0
+		if strings.TrimSpace(entreeProvisoire.Text) != "" {
+			if provisoire, err = lirePourcentage(entreeProvisoire, "coefficient provisoire"); err != nil {
+				return err
+			}
+		}
+
+		decimales := c.reglages.decimales()
+		f := func(n float64) string { return formaterDecimales(n, decimales) }
+		pct := func(k float64) string { return formaterCle(arrondir(k*100, 4)) + " %" }
+		definitif := k.deduction()
+		t.lignes = [][]string{
+			{"Coefficient d'assujettissement", pct(k.assujettissement)},
+			{"Coefficient de taxation", pct(k.taxation)},
+			{"Coefficient d'admission", pct(k.admission)},
+			{"Coefficient de déduction", pct(definitif)},
+		}
+		c.ajouterHistorique(fmt.Sprintf("Coefficient de déduction %s × %s × %s = %s",
+			pct(k.assujettissement), pct(k.taxation), pct(k.admission), pct(definitif)))
+
+		switch {
+		case tva == 0:
+			resultat.SetText(fmt.Sprintf("Coefficient de déduction : %s", pct(definitif)))
+		case provisoire < 0:
+			deductible := arrondir(tva*definitif, decimales)
+			t.lignes = append(t.lignes,
+				[]string{"TVA grevant les dépenses", f(tva)},
+				[]string{"TVA déductible", f(deductible)})
+			resultat.SetText(fmt.Sprintf("TVA déductible : %s (coefficient %s)", f(deductible), pct(definitif)))
+			c.afficherResultat(fmt.Sprintf("TVA déductible %s × %s", f(tva), pct(definitif)), deductible)
+		default:
+			r := calculerRegularisation(tva, provisoire, definitif, decimales)
+			t.lignes = append(t.lignes,
+				[]string{"TVA grevant les dépenses", f(tva)},
+				[]string{"Coefficient provisoire", pct(provisoire)},
+				[]string{"TVA déduite (provisoire)", f(r.provisoire)},
+				[]string{"TVA déductible (définitive)", f(r.definitive)},
+				[]string{"Régularisation", f(r.ecart)})
+			c.ajouterHistorique(fmt.Sprintf("TVA déduite %s × %s (provisoire) = %s", f(tva), pct(provisoire), f(r.provisoire)))
+			c.ajouterHistorique(fmt.Sprintf("TVA déductible %s × %s (définitif) = %s", f(tva), pct(definitif), f(r.definitive)))
+			switch {
+			case r.ecart > 0:
+				resultat.SetText(fmt.Sprintf("Déduction complémentaire : %s (CA3 ligne 21)", f(r.ecart)))
+				c.afficherResultat("Régularisation du coefficient : déduction complémentaire", r.ecart)
+			case r.ecart < 0:
+				resultat.SetText(fmt.Sprintf("TVA à reverser : %s (CA3 ligne 15)", f(-r.ecart)))
+				c.afficherResultat("Régularisation du coefficient : TVA à reverser", -r.ecart)
+			default:
+				resultat.SetText("Pas de régularisation : coefficients identiques")
+				c.afficherResultat("Régularisation du coefficient", 0)
+			}
+		}
+		ajusterColonnes(table, t)
+		return nil
+	}
+
+	btnCalculer := widget.NewButton("Calculer", func() {
+		if err := calculer(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	btnCalculer.Importance = widget.HighImportance
+
+	aide := widget.NewLabel("Sans coefficient provisoire, la TVA déductible est calculée avec le coefficient de l'année. " +
+		"Avec un coefficient provisoire, l'écart donne la régularisation de fin d'année.")
+	aide.TextStyle = fyne.TextStyle{Italic: true}
+	aide.Wrapping = fyne.TextWrapWord
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Assujettissement %", entreeAssujettissement),
+		widget.NewFormItem("CA ouvrant droit", entreeCATaxable),
+		widget.NewFormItem("CA total", entreeCATotal),
+		widget.NewFormItem("Admission %", entreeAdmission),
+		widget.NewFormItem("TVA des dépenses", entreeTVA),
+		widget.NewFormItem("Coefficient provisoire", entreeProvisoire),
+	)
+	haut := container.NewVBox(formulaire, aide, btnCalculer, resultat)
+	w.SetContent(container.NewPadded(container.NewBorder(haut, c.boutonsExport(t, w), nil, nil, table)))
+	w.Show()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ========================================
+// DOSSIER DE CONFIGURATION
+// ========================================
+
+// Les fichiers de configuration (taux, barèmes...) sont rangés dans un
+// dossier "config" à côté de l'exécutable : l'application reste portable.
+const nomDossierConfig = "config"
+
+func dossierConfig() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return nomDossierConfig
+	}
+	return filepath.Join(filepath.Dir(exe), nomDossierConfig)
+}
+
+func cheminConfig(nom string) string {
+	return filepath.Join(dossierConfig(), nom)
+}
+
+// Écrit un fichier dans le dossier de configuration (créé si besoin)
+func ecrireFichierConfig(nom string, contenu []byte) error {
+	if err := os.MkdirAll(dossierConfig(), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(cheminConfig(nom), contenu, 0o644)
+}
+
+// Lit un fichier de table éditable (taux, barèmes...). S'il n'existe pas
+// encore, la version par défaut intégrée à l'exe y est copiée pour que
+// l'utilisateur puisse la modifier sans recompiler.
+func lireTableConfig(nom string, defaut []byte) ([]byte, error) {
+	donnees, err := os.ReadFile(cheminConfig(nom))
+	if err == nil {
+		return donnees, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	ecrireFichierConfig(nom, defaut)
+	return defaut, nil
+}
+
+// Découpe un fichier CSV (séparateur ";") en lignes de champs,
+// en ignorant les lignes vides et les commentaires "#"
+func lireLignesCSV(donnees []byte) [][]string {
+	var lignes [][]string
+	for _, ligne := range strings.Split(string(donnees), "\n") {
+		ligne = strings.TrimSpace(strings.TrimPrefix(ligne, "\ufeff"))
+		if ligne == "" || strings.HasPrefix(ligne, "#") {
+			continue
+		}
+		champs := strings.Split(ligne, ";")
+		for i := range champs {
+			champs[i] = strings.TrimSpace(champs[i])
+		}
+		lignes = append(lignes, champs)
+	}
+	return lignes
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// CONVERSION DES MONNAIES NATIONALES (EURO)
+// ========================================
+
+// Règles du règlement (CE) n° 1103/97 :
+//   - taux fixés avec six chiffres significatifs (1 EUR = taux unités) ;
+//   - pas de taux inverse : on divise par le taux vers l'euro, on multiplie depuis l'euro ;
+//   - entre deux monnaies nationales, triangulation obligatoire par l'euro,
+//     le montant intermédiaire en euros étant arrondi à au moins trois décimales.
+
+type monnaieNationale struct {
+	code      string
+	nom       string
+	taux      float64 // Nombre d'unités pour 1 EUR
+	decimales int     // Décimales de la monnaie nationale
+}
+
+// Taux de conversion irrévocables
+var MonnaiesNationales = []monnaieNationale{
+	{"FRF", "Franc français", 6.55957, 2},
+	{"DEM", "Mark allemand", 1.95583, 2},
+	{"BEF", "Franc belge", 40.3399, 0},
+	{"LUF", "Franc luxembourgeois", 40.3399, 0},
+	{"ITL", "Lire italienne", 1936.27, 0},
+	{"ESP", "Peseta espagnole", 166.386, 0},
+	{"NLG", "Florin néerlandais", 2.20371, 2},
+	{"ATS", "Schilling autrichien", 13.7603, 2},
+	{"PTE", "Escudo portugais", 200.482, 0},
+	{"FIM", "Mark finlandais", 5.94573, 2},
+	{"IEP", "Livre irlandaise", 0.787564, 2},
+	{"GRD", "Drachme grecque", 340.750, 0},
+	{"SIT", "Tolar slovène", 239.640, 2},
+	{"CYP", "Livre chypriote", 0.585274, 2},
+	{"MTL", "Lire maltaise", 0.429300, 2},
+	{"SKK", "Couronne slovaque", 30.1260, 2},
+	{"EEK", "Couronne estonienne", 15.6466, 2},
+	{"LVL", "Lats letton", 0.702804, 2},
+	{"LTL", "Litas lituanien", 3.45280, 2},
+	{"HRK", "Kuna croate", 7.53450, 2},
+}
+
+// Décimales du montant intermédiaire en euros lors d'une triangulation
+const decimalesTriangulation = 3
+
+func trouverMonnaieNationale(code string) (monnaieNationale, bool) {
+	for _, m := range MonnaiesNationales {
+		if m.code == code {
+			return m, true
+		}
+	}
+	return monnaieNationale{}, false
+}
+
+// Monnaie nationale -> euro : division par le taux, arrondi au centime
+func versEuro(montant float64, m monnaieNationale) float64 {
+	return arrondir(montant/m.taux, 2)
+}
+
+// Euro -> monnaie nationale : multiplication par le taux, arrondi à l'unité divisionnaire
+func depuisEuro(montant float64, m monnaieNationale) float64 {
+	return arrondir(montant*m.taux, m.decimales)
+}
+
+// Monnaie nationale -> monnaie nationale par triangulation via l'euro.
+// Retourne aussi le montant intermédiaire en euros.
+func trianguler(montant float64, de, vers monnaieNationale) (resultat, euros float64) {
+	euros = arrondir(montant/de.taux, decimalesTriangulation)
+	return arrondir(euros*vers.taux, vers.decimales), euros
+}
+
+// Affiche un taux avec ses six chiffres significatifs (ex. 340,750)
+func formaterTauxFixe(taux float64) string {
+	chiffres := int(math.Floor(math.Log10(taux))) + 1
+	decimales := 6 - chiffres
+	if decimales < 0 {
+		decimales = 0
+	}
+	return formaterDecimales(taux, decimales)
+}
+
+// ========================================
+// FONCTIONS DE CONVERSION (BOUTONS)
+// ========================================
+
+func (c *Calculatrice) francVersEuro() {
+	c.convertirMonnaie("FRF", "EUR")
+}
+
+func (c *Calculatrice) euroVersFranc() {
+	c.convertirMonnaie("EUR", "FRF")
+}
+
+// Convertit la valeur courante entre deux monnaies (codes ISO, "EUR" compris)
+func (c *Calculatrice) convertirMonnaie(de, vers string) {
+	valeur := c.obtenirValeurCourante()
+	if valeur == 0 || de == vers {
+		return
+	}
+
+	mDe, deNational := trouverMonnaieNationale(de)
+	mVers, versNational := trouverMonnaieNationale(vers)
+
+	var resultat float64
+	var expression string
+
+	switch {
+	case deNational && vers == "EUR":
+		resultat = versEuro(valeur, mDe)
+		expression = fmt.Sprintf("%s %s > EUR (/ %s)", c.formaterResultat(valeur), de, formaterTauxFixe(mDe.taux))
+	case de == "EUR" && versNational:
+		resultat = depuisEuro(valeur, mVers)
+		expression = fmt.Sprintf("%s EUR > %s (x %s)", c.formaterResultat(valeur), vers, formaterTauxFixe(mVers.taux))
+	case deNational && versNational:
+		var euros float64
+		resultat, euros = trianguler(valeur, mDe, mVers)
+		expression = fmt.Sprintf("%s %s > %s (via %s EUR)", c.formaterResultat(valeur), de, vers,
+			formaterDecimales(euros, decimalesTriangulation))
+	default:
+		return
+	}
+
+	// Décimales de la monnaie d'arrivée, sans arrondi espèces de la devise de travail
+	decimales := 2
+	if versNational {
+		decimales = mVers.decimales
+	}
+	c.afficherMontant(expression, resultat, formaterDecimales(resultat, decimales))
+}
+
+// Boîte de dialogue pour choisir les monnaies nationales à convertir
+func (c *Calculatrice) dialogueMonnaies() {
+	codes := []string{"EUR"}
+	for _, m := range MonnaiesNationales {
+		codes = append(codes, m.code)
+	}
+
+	selectDe := widget.NewSelect(codes, nil)
+	selectDe.SetSelected("FRF")
+	selectVers := widget.NewSelect(codes, nil)
+	selectVers.SetSelected("EUR")
+
+	infoTaux := widget.NewLabel("")
+	majInfo := func(string) {
+		var lignes []string
+		for _, code := range []string{selectDe.Selected, selectVers.Selected} {
+			if m, ok := trouverMonnaieNationale(code); ok {
+				lignes = append(lignes, fmt.Sprintf("1 EUR = %s %s (%s)", formaterTauxFixe(m.taux), m.code, m.nom))
+			}
+		}
+		infoTaux.SetText(strings.Join(lignes, "\n"))
+	}
+	selectDe.OnChanged = majInfo
+	selectVers.OnChanged = majInfo
+	majInfo("")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("De", selectDe),
+		widget.NewFormItem("Vers", selectVers),
+		widget.NewFormItem("Taux", infoTaux),
+	}
+
+	dialog.ShowForm("Monnaies nationales (euro)", "Convertir", "Annuler", items, func(ok bool) {
+		if ok {
+			c.convertirMonnaie(selectDe.Selected, selectVers.Selected)
+		}
+	}, c.fenetre)
+}
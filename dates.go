@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ========================================
+// DATES ET CONDITIONS DE PAIEMENT
+// ========================================
+
+const FormatDate = "02/01/2006"
+
+// Lit une date JJ/MM/AAAA (ou JJ/MM/AA, JJ.MM.AAAA, AAAA-MM-JJ)
+func lireDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	s = strings.NewReplacer(".", "/", "-", "/").Replace(s)
+	for _, format := range []string{"02/01/2006", "2/1/2006", "02/01/06", "2/1/06", "2006/01/02"} {
+		if d, err := time.Parse(format, s); err == nil {
+			return d, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date invalide « %s » (format JJ/MM/AAAA)", s)
+}
+
+func formaterDate(d time.Time) string {
+	return d.Format(FormatDate)
+}
+
+// Date du jour sans l'heure
+func aujourdhui() time.Time {
+	y, m, d := time.Now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func finDeMois(d time.Time) time.Time {
+	return time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+}
+
+// Ajoute n mois en restant dans le mois visé (31/01 + 1 mois = 28 ou 29/02)
+func ajouterMois(d time.Time, n int) time.Time {
+	premier := time.Date(d.Year(), d.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	jour := d.Day()
+	if dernier := finDeMois(premier).Day(); jour > dernier {
+		jour = dernier
+	}
+	return time.Date(premier.Year(), premier.Month(), jour, 0, 0, 0, 0, time.UTC)
+}
+
+// Modes de calcul d'une condition de paiement
+const (
+	TermeNet          = iota // Date + X jours
+	TermeFinDeMois           // Date + X jours, puis fin de mois ("45 jours fin de mois")
+	TermeFinDeMoisNet        // Fin de mois, puis + X jours ("fin de mois + 45 jours")
+)
+
+type conditionPaiement struct {
+	libelle string
+	jours   int
+	mode    int
+}
+
+// Conditions de paiement usuelles
+var ConditionsPaiement = []conditionPaiement{
+	{"À réception", 0, TermeNet},
+	{"30 jours net", 30, TermeNet},
+	{"45 jours net", 45, TermeNet},
+	{"60 jours net", 60, TermeNet},
+	{"Fin de mois", 0, TermeFinDeMois},
+	{"30 jours fin de mois", 30, TermeFinDeMois},
+	{"45 jours fin de mois", 45, TermeFinDeMois},
+	{"Fin de mois + 10 jours", 10, TermeFinDeMoisNet},
+	{"Fin de mois + 45 jours", 45, TermeFinDeMoisNet},
+}
+
+// Date d'échéance d'une facture datée d selon la condition
+func (cp conditionPaiement) echeance(d time.Time) time.Time {
+	switch cp.mode {
+	case TermeFinDeMois:
+		return finDeMois(d.AddDate(0, 0, cp.jours))
+	case TermeFinDeMoisNet:
+		return finDeMois(d).AddDate(0, 0, cp.jours)
+	default:
+		return d.AddDate(0, 0, cp.jours)
+	}
+}
+
+func libellesConditions() []string {
+	var libelles []string
+	for _, cp := range ConditionsPaiement {
+		libelles = append(libelles, cp.libelle)
+	}
+	return libelles
+}
+
+func trouverCondition(libelle string) conditionPaiement {
+	for _, cp := range ConditionsPaiement {
+		if cp.libelle == libelle {
+			return cp
+		}
+	}
+	return ConditionsPaiement[0]
+}
+
+// Libellé d'une condition saisie librement (mode + nombre de jours)
+func nouvelleCondition(mode, jours int) conditionPaiement {
+	var libelle string
+	switch mode {
+	case TermeFinDeMois:
+		libelle = fmt.Sprintf("%d jours fin de mois", jours)
+	case TermeFinDeMoisNet:
+		libelle = fmt.Sprintf("Fin de mois + %d jours", jours)
+	default:
+		libelle = fmt.Sprintf("%d jours net", jours)
+	}
+	return conditionPaiement{libelle, jours, mode}
+}
+
+// ========================================
+// DÉLAIS LÉGAUX (LME, ARTICLE L441-10 DU CODE DE COMMERCE)
+// ========================================
+
+// Plafonds de délai de paiement entre professionnels
+const (
+	DelaiLMEJours          = 60 // 60 jours à compter de la date de facture
+	DelaiLMEJoursFinDeMois = 45 // ou 45 jours fin de mois, si convenu
+)
+
+// Dates limites LME pour une facture : 60 jours date de facture et
+// 45 jours fin de mois (les deux modes de calcul admis sont retenus,
+// la limite la plus tardive s'applique)
+func limitesLME(facture time.Time) (soixanteJours, quaranteCinqFDM time.Time) {
+	soixanteJours = facture.AddDate(0, 0, DelaiLMEJours)
+	quaranteCinqFDM = finDeMois(facture.AddDate(0, 0, DelaiLMEJoursFinDeMois))
+	if autre := finDeMois(facture).AddDate(0, 0, DelaiLMEJoursFinDeMois); autre.After(quaranteCinqFDM) {
+		quaranteCinqFDM = autre
+	}
+	return soixanteJours, quaranteCinqFDM
+}
+
+// Vrai si l'échéance dépasse les deux plafonds légaux
+func depasseLME(facture, echeance time.Time) bool {
+	soixante, fdm := limitesLME(facture)
+	return echeance.After(soixante) && echeance.After(fdm)
+}
+
+// ========================================
+// JOURS FÉRIÉS ET JOURS OUVRÉS
+// ========================================
+
+// Dimanche de Pâques (algorithme de Meeus/Jones/Butcher, calendrier grégorien)
+func dimanchePaques(annee int) time.Time {
+	a := annee % 19
+	b := annee / 100
+	c := annee % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	mois := (h + l - 7*m + 114) / 31
+	jour := (h+l-7*m+114)%31 + 1
+	return time.Date(annee, time.Month(mois), jour, 0, 0, 0, 0, time.UTC)
+}
+
+// Jours fériés légaux en France métropolitaine (Alsace-Moselle en option)
+func joursFeries(annee int, alsaceMoselle bool) map[time.Time]string {
+	date := func(mois time.Month, jour int) time.Time {
+		return time.Date(annee, mois, jour, 0, 0, 0, 0, time.UTC)
+	}
+	paques := dimanchePaques(annee)
+
+	feries := map[time.Time]string{
+		date(time.January, 1):    "Jour de l'an",
+		paques.AddDate(0, 0, 1):  "Lundi de Pâques",
+		date(time.May, 1):        "Fête du travail",
+		date(time.May, 8):        "Victoire 1945",
+		paques.AddDate(0, 0, 39): "Ascension",
+		paques.AddDate(0, 0, 50): "Lundi de Pentecôte",
+		date(time.July, 14):      "Fête nationale",
+		date(time.August, 15):    "Assomption",
+		date(time.November, 1):   "Toussaint",
+		date(time.November, 11):  "Armistice 1918",
+		date(time.December, 25):  "Noël",
+	}
+	if alsaceMoselle {
+		feries[paques.AddDate(0, 0, -2)] = "Vendredi saint"
+		feries[date(time.December, 26)] = "Saint-Étienne"
+	}
+	return feries
+}
+
+// Calendrier des jours fériés, calculé à la demande par année
+type calendrierFeries struct {
+	alsaceMoselle bool
+	annees        map[int]map[time.Time]string
+}
+
+func nouveauCalendrier(alsaceMoselle bool) *calendrierFeries {
+	return &calendrierFeries{alsaceMoselle: alsaceMoselle, annees: make(map[int]map[time.Time]string)}
+}
+
+func (cal *calendrierFeries) ferie(d time.Time) (string, bool) {
+	feries, ok := cal.annees[d.Year()]
+	if !ok {
+		feries = joursFeries(d.Year(), cal.alsaceMoselle)
+		cal.annees[d.Year()] = feries
+	}
+	nom, ok := feries[d]
+	return nom, ok
+}
+
+// Jour ouvré : du lundi au vendredi, hors jours fériés
+func (cal *calendrierFeries) ouvre(d time.Time) bool {
+	if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		return false
+	}
+	_, ferie := cal.ferie(d)
+	return !ferie
+}
+
+// Jour ouvrable : du lundi au samedi, hors jours fériés
+func (cal *calendrierFeries) ouvrable(d time.Time) bool {
+	if d.Weekday() == time.Sunday {
+		return false
+	}
+	_, ferie := cal.ferie(d)
+	return !ferie
+}
+
+// Nombre de jours calendaires entre deux dates (fin - début)
+func joursEntre(debut, fin time.Time) int {
+	return int(fin.Sub(debut).Hours() / 24)
+}
+
+// Compte les jours vérifiant le critère, du lendemain de début jusqu'à fin inclus
+func (cal *calendrierFeries) compterJours(debut, fin time.Time, critere func(time.Time) bool) int {
+	sens := 1
+	if fin.Before(debut) {
+		debut, fin, sens = fin, debut, -1
+	}
+	n := 0
+	for d := debut.AddDate(0, 0, 1); !d.After(fin); d = d.AddDate(0, 0, 1) {
+		if critere(d) {
+			n++
+		}
+	}
+	return sens * n
+}
+
+// Ajoute n jours ouvrés à une date (n négatif pour reculer)
+func (cal *calendrierFeries) ajouterJoursOuvres(d time.Time, n int) time.Time {
+	pas := 1
+	if n < 0 {
+		pas, n = -1, -n
+	}
+	for n > 0 {
+		d = d.AddDate(0, 0, pas)
+		if cal.ouvre(d) {
+			n--
+		}
+	}
+	return d
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// DEVIS / FACTURE (LIGNES, TOTAUX PAR TAUX)
+// ========================================
+
+const (
+	ArrondiTVAParLigne = "TVA arrondie par ligne"
+	ArrondiTVAParTaux  = "TVA arrondie par taux"
+)
+
+type ligneDevis struct {
+	description string
+	quantite    float64
+	prixHT      float64 // Prix unitaire HT
+	remise      float64 // Remise de ligne en %
+	tauxTVA     float64
+}
+
+// Montant HT de la ligne, remise déduite
+func (l ligneDevis) montantHT(decimales int) float64 {
+	return arrondir(l.quantite*l.prixHT*(1-l.remise/100), decimales)
+}
+
+// Base et TVA pour un taux
+type totalTaux struct {
+	taux   float64
+	baseHT float64 // Après remise globale
+	tva    float64
+}
+
+type totauxDevis struct {
+	totalHT       float64 // Somme des lignes
+	remiseGlobale float64 // Montant de la remise globale
+	netHT         float64
+	parTaux       []totalTaux
+	totalTVA      float64
+	totalTTC      float64
+	acompte       float64
+	solde         float64
+}
+
+// Calcule les totaux. La remise globale (en %) s'applique à la base de chaque
+// taux ; la TVA est arrondie par ligne ou une seule fois par taux selon le mode.
+func calculerDevis(lignes []ligneDevis, remiseGlobale float64, acompte string, modeArrondi string, decimales int) (totauxDevis, error) {
+	var tot totauxDevis
+	bases := make(map[float64]float64)
+	tvas := make(map[float64]float64)
+
+	for _, l := range lignes {
+		ht := l.montantHT(decimales)
+		tot.totalHT += ht
+		net := arrondir(ht*(1-remiseGlobale/100), decimales)
+		bases[l.tauxTVA] += net
+		if modeArrondi == ArrondiTVAParLigne {
+			tvas[l.tauxTVA] += arrondir(net*l.tauxTVA/100, decimales)
+		}
+	}
+
+	for taux, base := range bases {
+		base = arrondir(base, decimales)
+		tva := arrondir(tvas[taux], decimales)
+		if modeArrondi != ArrondiTVAParLigne {
+			tva = arrondir(base*taux/100, decimales)
+		}
+		tot.parTaux = append(tot.parTaux, totalTaux{taux, base, tva})
+		tot.netHT += base
+		tot.totalTVA += tva
+	}
+	sort.Slice(tot.parTaux, func(i, j int) bool { return tot.parTaux[i].taux > tot.parTaux[j].taux })
+
+	tot.totalHT = arrondir(tot.totalHT, decimales)
+	tot.netHT = arrondir(tot.netHT, decimales)
+	tot.remiseGlobale = arrondir(tot.totalHT-tot.netHT, decimales)
+	tot.totalTVA = arrondir(tot.totalTVA, decimales)
+	tot.totalTTC = arrondir(tot.netHT+tot.totalTVA, decimales)
+
+	// Acompte en montant ou en pourcentage du TTC ("30 %")
+	if acompte = strings.TrimSpace(acompte); acompte != "" {
+		if strings.HasSuffix(acompte, "%") {
+			pct, err := lireNombre(strings.TrimSuffix(acompte, "%"))
+			if err != nil {
+				return tot, errors.New("acompte invalide")
+			}
+			tot.acompte = arrondir(tot.totalTTC*pct/100, decimales)
+		} else {
+			montant, err := lireNombre(acompte)
+			if err != nil {
+				return tot, errors.New("acompte invalide")
+			}
+			tot.acompte = arrondir(montant, decimales)
+		}
+	}
+	tot.solde = arrondir(tot.totalTTC-tot.acompte, decimales)
+	return tot, nil
+}
+
+// ========================================
+// DOCUMENT HTML IMPRIMABLE
+// ========================================
+
+type documentDevis struct {
+	Type, Numero, Date, Client string
+	Lignes                     [][]string
+	Totaux                     [][2]string
+	Mentions                   string
+}
+
+var modeleDevis = template.Must(template.New("devis").Parse(`<!DOCTYPE html>
+<html lang="fr"><head><meta charset="utf-8"><title>{{.Type}} {{.Numero}}</title>
+<style>
+body { font-family: Arial, sans-serif; margin: 2cm; color: #222; }
+h1 { color: #1B4D3E; }
+table { border-collapse: collapse; width: 100%; margin-top: 1em; }
+th, td { border: 1px solid #ccc; padding: 6px 8px; }
+th { background: #1B4D3E; color: #fff; }
+td.n { text-align: right; white-space: nowrap; }
+table.totaux { width: 45%; margin-left: auto; }
+table.totaux tr:last-child td { font-weight: bold; }
+.mentions { margin-top: 2em; font-size: 0.85em; color: #555; }
+@media print { body { margin: 1cm; } }
+</style></head><body>
+<h1>{{.Type}} n° {{.Numero}}</h1>
+<p>Date : {{.Date}}{{if .Client}}<br>Client : {{.Client}}{{end}}</p>
+<table>
+<tr><th>Description</th><th>Qté</th><th>PU HT</th><th>Remise</th><th>TVA</th><th>Montant HT</th></tr>
+{{range .Lignes}}<tr><td>{{index . 0}}</td><td class="n">{{index . 1}}</td><td class="n">{{index . 2}}</td><td class="n">{{index . 3}}</td><td class="n">{{index . 4}}</td><td class="n">{{index . 5}}</td></tr>
+{{end}}</table>
+<table class="totaux">
+{{range .Totaux}}<tr><td>{{index . 0}}</td><td class="n">{{index . 1}}</td></tr>
+{{end}}</table>
+{{if .Mentions}}<p class="mentions">{{.Mentions}}</p>{{end}}
+</body></html>
+`))
+
+func genererHTMLDevis(doc documentDevis) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := modeleDevis.Execute(&buf, doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ========================================
+// FENÊTRE DEVIS / FACTURE
+// ========================================
+
+func (c *Calculatrice) fenetreDevis() {
+	w := c.nouvelleFenetre("Devis / facture", 1000, 750)
+
+	// En-tête du document
+	selectType := widget.NewSelect([]string{"Devis", "Facture", "Facture d'acompte"}, nil)
+	selectType.SetSelected("Devis")
+	entreeNumero := widget.NewEntry()
+	entreeNumero.SetPlaceHolder("Numéro")
+	entreeDate := widget.NewEntry()
+	entreeDate.SetText(formaterDate(aujourdhui()))
+	entreeClient := widget.NewEntry()
+	entreeClient.SetPlaceHolder("Client")
+
+	entreeRemise := widget.NewEntry()
+	entreeRemise.SetPlaceHolder("Remise globale %")
+	entreeAcompte := widget.NewEntry()
+	entreeAcompte.SetPlaceHolder("Acompte (montant ou %)")
+	selectArrondi := widget.NewSelect([]string{ArrondiTVAParTaux, ArrondiTVAParLigne}, nil)
+	selectArrondi.SetSelected(ArrondiTVAParTaux)
+
+	// Saisie d'une ligne
+	entreeDescription := widget.NewEntry()
+	entreeDescription.SetPlaceHolder("Description")
+	entreeQuantite := widget.NewEntry()
+	entreeQuantite.SetText("1")
+	entreePrix := widget.NewEntry()
+	entreePrix.SetPlaceHolder("PU HT")
+	entreeRemiseLigne := widget.NewEntry()
+	entreeRemiseLigne.SetPlaceHolder("Remise %")
+	selectTaux := widget.NewSelect(append(c.reglages.libellesTauxTVA(), "0"), nil)
+	selectTaux.SetSelectedIndex(0)
+
+	var lignes []ligneDevis
+	var totaux totauxDevis
+	t := &tableau{
+		titre:   "Devis",
+		entetes: []string{"Description", "Qté", "PU HT", "Remise %", "TVA %", "Montant HT"},
+	}
+	table := nouveauTableauWidget(t)
+	libelleTotaux := widget.NewLabel("")
+	libelleTotaux.TextStyle = fyne.TextStyle{Monospace: true}
+
+	// Lignes de totaux (affichage, CSV et HTML)
+	lignesTotaux := func(decimales int) [][2]string {
+		f := func(n float64) string { return formaterDecimales(n, decimales) }
+		lt := [][2]string{{"Total HT", f(totaux.totalHT)}}
+		if totaux.remiseGlobale != 0 {
+			lt = append(lt, [2]string{"Remise globale", "-" + f(totaux.remiseGlobale)}, [2]string{"Net HT", f(totaux.netHT)})
+		}
+		for _, pt := range totaux.parTaux {
+			lt = append(lt, [2]string{fmt.Sprintf("TVA %s %% sur %s", formaterCle(pt.taux), f(pt.baseHT)), f(pt.tva)})
+		}
+		lt = append(lt, [2]string{"Total TTC", f(totaux.totalTTC)})
+		if totaux.acompte != 0 {
+			lt = append(lt, [2]string{"Acompte", "-" + f(totaux.acompte)}, [2]string{"Solde à payer", f(totaux.solde)})
+		}
+		return lt
+	}
+
+	recalculer := func() error {
+		decimales := c.reglages.decimales()
+		var remise float64
+		if strings.TrimSpace(entreeRemise.Text) != "" {
+			var err error
+			if remise, err = lireNombre(strings.TrimSuffix(strings.TrimSpace(entreeRemise.Text), "%")); err != nil {
+				return errors.New("remise globale invalide")
+			}
+		}
+		var err error
+		totaux, err = calculerDevis(lignes, remise, entreeAcompte.Text, selectArrondi.Selected, decimales)
+		if err != nil {
+			return err
+		}
+
+		t.titre = strings.TrimSpace(selectType.Selected + " " + entreeNumero.Text)
+		t.lignes = nil
+		for _, l := range lignes {
+			t.lignes = append(t.lignes, []string{l.description, formaterCle(l.quantite),
+				formaterDecimales(l.prixHT, decimales), formaterCle(l.remise), formaterCle(l.tauxTVA),
+				formaterDecimales(l.montantHT(decimales), decimales)})
+		}
+		var texte []string
+		for _, lt := range lignesTotaux(decimales) {
+			t.lignes = append(t.lignes, []string{lt[0], "", "", "", "", lt[1]})
+			texte = append(texte, fmt.Sprintf("%-30s %15s", lt[0], lt[1]))
+		}
+		ajusterColonnes(table, t)
+		libelleTotaux.SetText(strings.Join(texte, "\n"))
+		return nil
+	}
+	recalculerOuErreur := func() {
+		if err := recalculer(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	}
+	entreeRemise.OnSubmitted = func(string) { recalculerOuErreur() }
+	entreeAcompte.OnSubmitted = func(string) { recalculerOuErreur() }
+	selectArrondi.OnChanged = func(string) { recalculerOuErreur() }
+
+	ajouter := func() {
+		quantite, err := lireNombre(entreeQuantite.Text)
+		if err != nil {
+			dialog.ShowError(errors.New("quantité invalide"), w)
+			return
+		}
+		prix, err := lireNombre(entreePrix.Text)
+		if err != nil {
+			dialog.ShowError(errors.New("prix unitaire invalide"), w)
+			return
+		}
+		var remise float64
+		if strings.TrimSpace(entreeRemiseLigne.Text) != "" {
+			if remise, err = lireNombre(strings.TrimSuffix(strings.TrimSpace(entreeRemiseLigne.Text), "%")); err != nil {
+				dialog.ShowError(errors.New("remise de ligne invalide"), w)
+				return
+			}
+		}
+		taux, _ := lireNombre(selectTaux.Selected)
+		lignes = append(lignes, ligneDevis{strings.TrimSpace(entreeDescription.Text), quantite, prix, remise, taux})
+		recalculerOuErreur()
+		entreeDescription.SetText("")
+		entreePrix.SetText("")
+		entreeRemiseLigne.SetText("")
+		entreeQuantite.SetText("1")
+		w.Canvas().Focus(entreeDescription)
+	}
+	entreePrix.OnSubmitted = func(string) { ajouter() }
+
+	document := func() documentDevis {
+		doc := documentDevis{
+			Type:   selectType.Selected,
+			Numero: entreeNumero.Text,
+			Date:   entreeDate.Text,
+			Client: entreeClient.Text,
+			Totaux: lignesTotaux(c.reglages.decimales()),
+		}
+		doc.Lignes = t.lignes[:len(lignes)]
+		if selectType.Selected != "Devis" {
+			doc.Mentions = fmt.Sprintf("En cas de retard de paiement : pénalités au taux BCE + %s points et indemnité forfaitaire de %s € pour frais de recouvrement.",
+				formaterCle(MajorationBCE), formaterCle(IndemniteRecouvrement))
+		}
+		return doc
+	}
+
+	exporterHTML := func() {
+		if err := recalculer(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		html, err := genererHTMLDevis(document())
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		c.exporterFichier(t.nomFichier(".html"), html, w)
+	}
+
+	// Aperçu dans le navigateur : Ctrl+P pour imprimer ou enregistrer en PDF
+	imprimer := func() {
+		if err := recalculer(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		html, err := genererHTMLDevis(document())
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		chemin := filepath.Join(os.TempDir(), "calculette-devis.html")
+		if err := os.WriteFile(chemin, html, 0o644); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		lien, _ := url.Parse(storage.NewFileURI(chemin).String())
+		if err := fyne.CurrentApp().OpenURL(lien); err != nil {
+			dialog.ShowError(err, w)
+		}
+	}
+
+	btnAjouter := widget.NewButton("Ajouter", ajouter)
+	btnAjouter.Importance = widget.HighImportance
+	btnSupprimer := widget.NewButton("Supprimer la dernière", func() {
+		if len(lignes) > 0 {
+			lignes = lignes[:len(lignes)-1]
+			recalculerOuErreur()
+		}
+	})
+	btnVider := widget.NewButton("Vider", func() {
+		lignes = nil
+		recalculerOuErreur()
+	})
+	btnHistorique := widget.NewButton("Vers l'historique", func() {
+		if err := recalculer(); err != nil || len(lignes) == 0 {
+			return
+		}
+		libelle := strings.TrimSpace(selectType.Selected + " " + entreeNumero.Text)
+		c.ajouterHistorique(fmt.Sprintf("%s : total HT = %s", libelle, formaterDecimales(totaux.netHT, c.reglages.decimales())))
+		c.ajouterHistorique(fmt.Sprintf("%s : TVA = %s", libelle, formaterDecimales(totaux.totalTVA, c.reglages.decimales())))
+		c.afficherResultat(libelle+" : total TTC", totaux.totalTTC)
+	})
+
+	entete := container.NewGridWithColumns(4, selectType, entreeNumero, entreeDate, entreeClient)
+	options := container.NewGridWithColumns(3, entreeRemise, entreeAcompte, selectArrondi)
+	saisie := container.NewBorder(nil, nil, nil, container.NewHBox(btnAjouter, btnSupprimer, btnVider),
+		container.NewGridWithColumns(5, entreeDescription, entreeQuantite, entreePrix, entreeRemiseLigne, selectTaux))
+	haut := container.NewVBox(entete, options, saisie)
+
+	exports := container.NewGridWithColumns(3,
+		widget.NewButton("Exporter HTML", exporterHTML),
+		widget.NewButton("Imprimer / PDF", imprimer),
+		btnHistorique,
+	)
+	bas := container.NewVBox(libelleTotaux, c.boutonsExport(t, w), exports)
+
+	recalculerOuErreur()
+	w.SetContent(container.NewPadded(container.NewBorder(haut, bas, nil, nil, table)))
+	w.Show()
+}
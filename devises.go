@@ -0,0 +1,385 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// CONVERSION DE DEVISES (TAUX HORS LIGNE)
+// ========================================
+
+// Fichiers de taux recherchés dans le dossier config, par ordre de priorité.
+// Le fichier quotidien de la BCE peut être déposé tel quel.
+var FichiersTaux = []string{"taux.csv", "taux.xml", "eurofxref-daily.xml"}
+
+// Décimales de l'unité divisionnaire (ISO 4217) quand elles diffèrent de 2
+var decimalesISO = map[string]int{
+	"JPY": 0, "KRW": 0, "ISK": 0, "CLP": 0, "VND": 0, "XOF": 0, "XAF": 0, "XPF": 0,
+	"PYG": 0, "UGX": 0, "RWF": 0, "KMF": 0, "GNF": 0, "DJF": 0, "VUV": 0,
+	"TND": 3, "KWD": 3, "BHD": 3, "JOD": 3, "OMR": 3, "LYD": 3, "IQD": 3,
+}
+
+func decimalesDevise(code string) int {
+	if d, ok := decimalesISO[code]; ok {
+		return d
+	}
+	return 2
+}
+
+// Table de taux : 1 unité de base = taux[devise] unités de devise
+type tableTaux struct {
+	base    string
+	date    string // AAAA-MM-JJ
+	taux    map[string]float64
+	fichier string
+}
+
+func (t *tableTaux) devises() []string {
+	codes := []string{t.base}
+	for code := range t.taux {
+		if code != t.base {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes[1:])
+	return codes
+}
+
+func (t *tableTaux) tauxDe(code string) (float64, bool) {
+	if code == t.base {
+		return 1, true
+	}
+	taux, ok := t.taux[code]
+	return taux, ok && taux > 0
+}
+
+// Convertit un montant d'une devise à l'autre en passant par la base.
+// Retourne le montant arrondi à l'unité divisionnaire de la devise cible
+// et le taux croisé appliqué (1 de = taux vers).
+func (t *tableTaux) convertir(montant float64, de, vers string) (float64, float64, error) {
+	tauxDe, ok := t.tauxDe(de)
+	if !ok {
+		return 0, 0, fmt.Errorf("devise inconnue : %s", de)
+	}
+	tauxVers, ok := t.tauxDe(vers)
+	if !ok {
+		return 0, 0, fmt.Errorf("devise inconnue : %s", vers)
+	}
+	croise := tauxVers / tauxDe
+	return arrondir(montant/tauxDe*tauxVers, decimalesDevise(vers)), croise, nil
+}
+
+// Date du fichier au format français (JJ/MM/AAAA)
+func (t *tableTaux) dateAffichee() string {
+	d, err := time.Parse("2006-01-02", t.date)
+	if err != nil {
+		return t.date
+	}
+	return d.Format("02/01/2006")
+}
+
+// ========================================
+// LECTURE DES FICHIERS DE TAUX
+// ========================================
+
+// Format XML quotidien de la BCE (eurofxref-daily.xml) :
+// <Cube><Cube time="2024-05-10"><Cube currency="USD" rate="1.0773"/>...
+type enveloppeBCE struct {
+	Cube struct {
+		Jours []struct {
+			Date string `xml:"time,attr"`
+			Taux []struct {
+				Devise string `xml:"currency,attr"`
+				Taux   string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+func lireTauxBCE(donnees []byte) (*tableTaux, error) {
+	var env enveloppeBCE
+	if err := xml.Unmarshal(donnees, &env); err != nil {
+		return nil, fmt.Errorf("XML BCE invalide : %w", err)
+	}
+	if len(env.Cube.Jours) == 0 {
+		return nil, errors.New("aucun taux dans le fichier BCE")
+	}
+
+	// Fichier historique : le premier jour est le plus récent
+	jour := env.Cube.Jours[0]
+	table := &tableTaux{base: "EUR", date: jour.Date, taux: make(map[string]float64)}
+	for _, t := range jour.Taux {
+		taux, err := strconv.ParseFloat(t.Taux, 64)
+		if err != nil {
+			return nil, fmt.Errorf("taux invalide pour %s : %s", t.Devise, t.Taux)
+		}
+		table.taux[t.Devise] = taux
+	}
+	return table, nil
+}
+
+// Format CSV simple, séparateur ";" (virgule décimale acceptée) ou "," :
+//
+//	# commentaire
+//	base;EUR
+//	date;2024-05-10
+//	USD;1,0773
+//	JPY;167,81
+func lireTauxCSV(donnees []byte) (*tableTaux, error) {
+	table := &tableTaux{base: "EUR", taux: make(map[string]float64)}
+
+	scanner := bufio.NewScanner(bytes.NewReader(donnees))
+	numLigne := 0
+	for scanner.Scan() {
+		numLigne++
+		ligne := strings.TrimSpace(scanner.Text())
+		if ligne == "" || strings.HasPrefix(ligne, "#") {
+			continue
+		}
+
+		sep := ";"
+		if !strings.Contains(ligne, ";") {
+			sep = ","
+		}
+		champs := strings.SplitN(ligne, sep, 2)
+		if len(champs) != 2 {
+			return nil, fmt.Errorf("ligne %d : format attendu DEVISE%sTAUX", numLigne, sep)
+		}
+		cle := strings.ToUpper(strings.TrimSpace(champs[0]))
+		valeur := strings.TrimSpace(champs[1])
+
+		switch cle {
+		case "BASE":
+			table.base = strings.ToUpper(valeur)
+		case "DATE":
+			table.date = valeur
+		case "DEVISE", "CURRENCY":
+			// Ligne d'en-tête
+		default:
+			if sep == ";" {
+				valeur = strings.ReplaceAll(valeur, ",", ".")
+			}
+			taux, err := strconv.ParseFloat(valeur, 64)
+			if err != nil || taux <= 0 {
+				return nil, fmt.Errorf("ligne %d : taux invalide pour %s", numLigne, cle)
+			}
+			table.taux[cle] = taux
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(table.taux) == 0 {
+		return nil, errors.New("aucun taux dans le fichier CSV")
+	}
+	return table, nil
+}
+
+func lireTaux(nom string, donnees []byte) (*tableTaux, error) {
+	if strings.HasSuffix(strings.ToLower(nom), ".xml") {
+		return lireTauxBCE(donnees)
+	}
+	return lireTauxCSV(donnees)
+}
+
+// Charge le premier fichier de taux présent dans le dossier config
+func chargerTableTaux() (*tableTaux, error) {
+	for _, nom := range FichiersTaux {
+		donnees, err := os.ReadFile(cheminConfig(nom))
+		if err != nil {
+			continue
+		}
+		table, err := lireTaux(nom, donnees)
+		if err != nil {
+			return nil, fmt.Errorf("%s : %w", nom, err)
+		}
+		table.fichier = nom
+		return table, nil
+	}
+	return nil, fmt.Errorf("aucun fichier de taux (%s) dans %s", strings.Join(FichiersTaux, ", "), dossierConfig())
+}
+
+// ========================================
+// FONCTIONS DE CONVERSION (BOUTONS)
+// ========================================
+
+// Recharge les taux depuis le dossier config et met à jour l'écran
+func (c *Calculatrice) chargerTauxChanges() error {
+	table, err := chargerTableTaux()
+	if err != nil {
+		c.tauxChanges = nil
+		c.majInfoEcran()
+		return err
+	}
+	c.tauxChanges = table
+	if c.deviseSource == "" {
+		c.deviseSource = table.base
+	}
+	if c.deviseCible == "" {
+		c.deviseCible = "USD"
+		if _, ok := table.tauxDe("USD"); !ok {
+			c.deviseCible = table.devises()[len(table.devises())-1]
+		}
+	}
+	c.majBoutonDevise()
+	return nil
+}
+
+func (c *Calculatrice) majBoutonDevise() {
+	if c.tauxChanges != nil {
+		c.btnDevise.SetText(fmt.Sprintf("%s>%s", c.deviseSource, c.deviseCible))
+	}
+	c.majInfoEcran()
+}
+
+// Convertit la valeur courante selon la paire de devises choisie
+func (c *Calculatrice) convertirDevise() {
+	if c.tauxChanges == nil {
+		c.dialogueDevises()
+		return
+	}
+	valeur := c.obtenirValeurCourante()
+	if valeur == 0 {
+		return
+	}
+
+	resultat, croise, err := c.tauxChanges.convertir(valeur, c.deviseSource, c.deviseCible)
+	if err != nil {
+		dialog.ShowError(err, c.fenetre)
+		return
+	}
+
+	expression := fmt.Sprintf("%s %s > %s (x %s au %s)", c.formaterResultat(valeur), c.deviseSource, c.deviseCible,
+		c.formaterNombre(strconv.FormatFloat(croise, 'g', 6, 64)), c.tauxChanges.dateAffichee())
+	c.afficherMontant(expression, resultat, formaterDecimales(resultat, decimalesDevise(c.deviseCible)))
+}
+
+// Inverse le sens de conversion (USD>EUR devient EUR>USD)
+func (c *Calculatrice) inverserDevises() {
+	if c.tauxChanges == nil {
+		return
+	}
+	c.deviseSource, c.deviseCible = c.deviseCible, c.deviseSource
+	c.majBoutonDevise()
+}
+
+// Choix de la paire de devises, rechargement et import du fichier de taux
+func (c *Calculatrice) dialogueDevises() {
+	selectDe := widget.NewSelect(nil, nil)
+	selectVers := widget.NewSelect(nil, nil)
+	infoFichier := widget.NewLabel("")
+	infoFichier.Wrapping = fyne.TextWrapWord
+
+	majListes := func() {
+		if c.tauxChanges == nil {
+			selectDe.Options, selectVers.Options = nil, nil
+			infoFichier.SetText(fmt.Sprintf("Déposez %s dans :\n%s", strings.Join(FichiersTaux, " ou "), dossierConfig()))
+		} else {
+			codes := c.tauxChanges.devises()
+			selectDe.Options, selectVers.Options = codes, codes
+			selectDe.SetSelected(c.deviseSource)
+			selectVers.SetSelected(c.deviseCible)
+			infoFichier.SetText(fmt.Sprintf("%s : %d devises, taux du %s",
+				c.tauxChanges.fichier, len(codes)-1, c.tauxChanges.dateAffichee()))
+		}
+		selectDe.Refresh()
+		selectVers.Refresh()
+	}
+
+	btnRecharger := widget.NewButton("Recharger", func() {
+		if err := c.chargerTauxChanges(); err != nil {
+			dialog.ShowError(err, c.fenetre)
+		}
+		majListes()
+	})
+
+	btnImporter := widget.NewButton("Importer...", func() {
+		c.importerFichierTaux(majListes)
+	})
+
+	majListes()
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("De", selectDe),
+		widget.NewFormItem("Vers", selectVers),
+		widget.NewFormItem("Fichier", infoFichier),
+		widget.NewFormItem("", container.NewGridWithColumns(2, btnRecharger, btnImporter)),
+	}
+
+	dialog.ShowForm("Devises", "Convertir", "Fermer", items, func(ok bool) {
+		if !ok || c.tauxChanges == nil || selectDe.Selected == "" || selectVers.Selected == "" {
+			return
+		}
+		c.deviseSource = selectDe.Selected
+		c.deviseCible = selectVers.Selected
+		c.majBoutonDevise()
+		c.convertirDevise()
+	}, c.fenetre)
+}
+
+// Copie un fichier de taux (XML BCE ou CSV) dans le dossier config
+func (c *Calculatrice) importerFichierTaux(apres func()) {
+	d := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+		if err != nil || r == nil {
+			return
+		}
+		defer r.Close()
+
+		donnees, err := io.ReadAll(r)
+		if err != nil {
+			dialog.ShowError(err, c.fenetre)
+			return
+		}
+
+		nom := "taux.csv"
+		if strings.ToLower(r.URI().Extension()) == ".xml" {
+			nom = "taux.xml"
+		}
+		if _, err := lireTaux(nom, donnees); err != nil {
+			dialog.ShowError(err, c.fenetre)
+			return
+		}
+
+		if err := ecrireFichierConfig(nom, donnees); err != nil {
+			dialog.ShowError(err, c.fenetre)
+			return
+		}
+		// Un seul fichier actif : les autres sont mis de côté en .bak pour
+		// respecter la priorité, une fois le nouveau fichier écrit
+		var erreurs []error
+		for _, autre := range FichiersTaux {
+			chemin := cheminConfig(autre)
+			if autre == nom {
+				continue
+			}
+			if err := os.Rename(chemin, chemin+".bak"); err != nil && !errors.Is(err, os.ErrNotExist) {
+				erreurs = append(erreurs, err)
+			}
+		}
+		if err := errors.Join(erreurs...); err != nil {
+			dialog.ShowError(fmt.Errorf("ancien fichier de taux non renommé, il peut rester prioritaire : %w", err), c.fenetre)
+		}
+		if err := c.chargerTauxChanges(); err != nil {
+			dialog.ShowError(err, c.fenetre)
+		}
+		apres()
+	}, c.fenetre)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".xml", ".csv"}))
+	d.Show()
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// ÉCHÉANCIER (PAIEMENT EN PLUSIEURS FOIS)
+// ========================================
+
+var Periodicites = map[string]int{
+	"Mensuelle":     1,
+	"Bimestrielle":  2,
+	"Trimestrielle": 3,
+	"Semestrielle":  6,
+	"Annuelle":      12,
+}
+
+var OrdrePeriodicites = []string{"Mensuelle", "Bimestrielle", "Trimestrielle", "Semestrielle", "Annuelle"}
+
+const (
+	EcartPremiere = "Première échéance"
+	EcartDerniere = "Dernière échéance"
+)
+
+// Nombre maximal d'échéances (30 ans de mensualités)
+const MaxEcheances = 360
+
+type echeance struct {
+	numero  int
+	base    time.Time // Date avant application de la condition de paiement
+	date    time.Time
+	montant float64
+}
+
+// Découpe un montant en n échéances égales ; l'écart d'arrondi est porté
+// par la première ou la dernière échéance. Les dates partent de debut,
+// espacées de moisEntre mois, puis passent par la condition de paiement.
+func calculerEcheancier(total float64, n int, debut time.Time, moisEntre int,
+	condition conditionPaiement, ecartSur string, decimales int) ([]echeance, error) {
+	if n < 1 || n > MaxEcheances {
+		return nil, fmt.Errorf("le nombre d'échéances doit être compris entre 1 et %d", MaxEcheances)
+	}
+	if total < 0 {
+		return nil, errors.New("le montant à échelonner ne peut pas être négatif")
+	}
+
+	unite := math.Pow10(decimales)
+	totalUnites := int64(math.Round(arrondir(total, decimales) * unite))
+	part := totalUnites / int64(n)
+	ecart := totalUnites - part*int64(n)
+
+	echeances := make([]echeance, n)
+	for i := range echeances {
+		montant := part
+		if (ecartSur == EcartPremiere && i == 0) || (ecartSur != EcartPremiere && i == n-1) {
+			montant += ecart
+		}
+		base := ajouterMois(debut, i*moisEntre)
+		echeances[i] = echeance{
+			numero:  i + 1,
+			base:    base,
+			date:    condition.echeance(base),
+			montant: float64(montant) / unite,
+		}
+	}
+	return echeances, nil
+}
+
+// ========================================
+// FENÊTRE ÉCHÉANCIER
+// ========================================
+
+func (c *Calculatrice) fenetreEcheancier() {
+	w := c.nouvelleFenetre("Échéancier", 800, 650)
+
+	entreeMontant := widget.NewEntry()
+	if v := c.obtenirValeurCourante(); v != 0 {
+		entreeMontant.SetText(c.formaterResultat(v))
+	}
+	entreeNombre := widget.NewEntry()
+	entreeNombre.SetText("3")
+
+	selectPeriodicite := widget.NewSelect(OrdrePeriodicites, nil)
+	selectPeriodicite.SetSelected("Mensuelle")
+
+	entreeDate := widget.NewEntry()
+	entreeDate.SetText(formaterDate(aujourdhui()))
+
+	selectCondition := widget.NewSelect(libellesConditions(), nil)
+	selectCondition.SetSelected(ConditionsPaiement[0].libelle)
+
+	selectEcart := widget.NewSelect([]string{EcartPremiere, EcartDerniere}, nil)
+	selectEcart.SetSelected(EcartDerniere)
+
+	t := &tableau{
+		titre:   "Echeancier",
+		entetes: []string{"N°", "Date de base", "Échéance", "Montant TTC", "Cumul"},
+	}
+	table := nouveauTableauWidget(t)
+
+	calculerClic := func() {
+		total, err := lireNombre(entreeMontant.Text)
+		if err != nil || total < 0 {
+			dialog.ShowError(errors.New("montant TTC invalide"), w)
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(entreeNombre.Text))
+		if err != nil || n < 1 || n > MaxEcheances {
+			dialog.ShowError(fmt.Errorf("nombre d'échéances invalide (1 à %d)", MaxEcheances), w)
+			return
+		}
+		debut, err := lireDate(entreeDate.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		decimales := c.reglages.decimales()
+		condition := trouverCondition(selectCondition.Selected)
+		echeances, err := calculerEcheancier(total, n, debut, Periodicites[selectPeriodicite.Selected],
+			condition, selectEcart.Selected, decimales)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		t.lignes = nil
+		var cumul float64
+		for _, e := range echeances {
+			cumul += e.montant
+			t.lignes = append(t.lignes, []string{
+				strconv.Itoa(e.numero),
+				formaterDate(e.base),
+				formaterDate(e.date),
+				formaterDecimales(e.montant, decimales),
+				formaterDecimales(cumul, decimales),
+			})
+		}
+		ajusterColonnes(table, t)
+
+		c.ajouterHistorique(fmt.Sprintf("Échéancier %s TTC en %d (%s, %s), 1re le %s = %s",
+			c.formaterResultat(total), n, selectPeriodicite.Selected, condition.libelle,
+			formaterDate(echeances[0].date), formaterDecimales(echeances[0].montant, decimales)))
+	}
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Montant TTC", entreeMontant),
+		widget.NewFormItem("Échéances", entreeNombre),
+		widget.NewFormItem("Périodicité", selectPeriodicite),
+		widget.NewFormItem("Date de départ", entreeDate),
+		widget.NewFormItem("Condition", selectCondition),
+		widget.NewFormItem("Écart d'arrondi", selectEcart),
+	)
+	btnCalculer := widget.NewButton("Calculer", calculerClic)
+	btnCalculer.Importance = widget.HighImportance
+
+	haut := container.NewVBox(formulaire, btnCalculer)
+	w.SetContent(container.NewPadded(container.NewBorder(haut, c.boutonsExport(t, w), nil, nil, table)))
+	w.Show()
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// ÉCRITURE COMPTABLE DEPUIS UN CALCUL DE TVA
+// ========================================
+
+// Décomposition d'un montant soumis à TVA
+type decompositionTVA struct {
+	ht, tva, ttc float64
+	taux         float64
+}
+
+func depuisHT(ht, taux float64, decimales int) *decompositionTVA {
+	ht = arrondir(ht, decimales)
+	tva := arrondir(ht*taux/100, decimales)
+	return &decompositionTVA{ht, tva, arrondir(ht+tva, decimales), taux}
+}
+
+func depuisTTC(ttc, taux float64, decimales int) *decompositionTVA {
+	ttc = arrondir(ttc, decimales)
+	ht := arrondir(ttc/(1+taux/100), decimales)
+	return &decompositionTVA{ht, arrondir(ttc-ht, decimales), ttc, taux}
+}
+
+// Comptes utilisés pour les écritures (plan comptable général par défaut)
+type ComptesEcriture struct {
+	JournalAchats     string `json:"journal_achats"`
+	Charge            string `json:"charge"`
+	Immobilisation    string `json:"immobilisation"`
+	TVADeductible     string `json:"tva_deductible"`
+	TVADeductibleImmo string `json:"tva_deductible_immo"`
+	Fournisseur       string `json:"fournisseur"`
+	JournalVentes     string `json:"journal_ventes"`
+	Produit           string `json:"produit"`
+	TVACollectee      string `json:"tva_collectee"`
+	Client            string `json:"client"`
+}
+
+func comptesParDefaut() ComptesEcriture {
+	return ComptesEcriture{
+		JournalAchats:     "AC",
+		Charge:            "607",
+		Immobilisation:    "2183",
+		TVADeductible:     "44566",
+		TVADeductibleImmo: "44562",
+		Fournisseur:       "401",
+		JournalVentes:     "VT",
+		Produit:           "707",
+		TVACollectee:      "44571",
+		Client:            "411",
+	}
+}
+
+const (
+	SensAchat = "Achat"
+	SensVente = "Vente"
+)
+
+var LibellesJournaux = map[string]string{SensAchat: "Journal des achats", SensVente: "Journal des ventes"}
+
+type ligneEcriture struct {
+	compte, libelle string
+	debit, credit   float64
+}
+
+// Ligne au débit ou au crédit ; un montant négatif (avoir) change de côté
+func nouvelleLigneEcriture(compte, libelle string, montant float64, auDebit bool) ligneEcriture {
+	if montant < 0 {
+		montant, auDebit = -montant, !auDebit
+	}
+	if auDebit {
+		return ligneEcriture{compte: compte, libelle: libelle, debit: montant}
+	}
+	return ligneEcriture{compte: compte, libelle: libelle, credit: montant}
+}
+
+type ecriture struct {
+	journal, journalLib string
+	date                time.Time
+	piece, libelle      string
+	lignes              []ligneEcriture
+}
+
+// Achat : charge et TVA déductible au débit, fournisseur au crédit.
+// Vente : client au débit, produit et TVA collectée au crédit.
+func proposerEcriture(sens string, d decompositionTVA, comptes ComptesEcriture, date time.Time, piece, libelle string) ecriture {
+	e := ecriture{journalLib: LibellesJournaux[sens], date: date, piece: piece, libelle: libelle}
+	ajouter := func(compte string, montant float64, auDebit bool) {
+		if montant != 0 {
+			e.lignes = append(e.lignes, nouvelleLigneEcriture(compte, libelle, montant, auDebit))
+		}
+	}
+	if sens == SensVente {
+		e.journal = comptes.JournalVentes
+		ajouter(comptes.Client, d.ttc, true)
+		ajouter(comptes.Produit, d.ht, false)
+		ajouter(comptes.TVACollectee, d.tva, false)
+	} else {
+		e.journal = comptes.JournalAchats
+		ajouter(comptes.Charge, d.ht, true)
+		ajouter(comptes.TVADeductible, d.tva, true)
+		ajouter(comptes.Fournisseur, d.ttc, false)
+	}
+	return e
+}
+
+func (e ecriture) totaux(decimales int) (debit, credit float64) {
+	for _, l := range e.lignes {
+		debit += l.debit
+		credit += l.credit
+	}
+	return arrondir(debit, decimales), arrondir(credit, decimales)
+}
+
+func (e ecriture) equilibree(decimales int) bool {
+	debit, credit := e.totaux(decimales)
+	return debit == credit
+}
+
+// Lignes au format FEC (colonnes ColonnesFEC), intitulés tirés du plan comptable
+func (e ecriture) lignesFEC(numero string, plan *planComptable) [][]string {
+	date := e.date.Format(FormatDateFEC)
+	var lignes [][]string
+	for _, l := range e.lignes {
+		lignes = append(lignes, []string{
+			e.journal, e.journalLib, numero, date, l.compte, plan.libelle(l.compte), "", "",
+			e.piece, date, l.libelle, montantFEC(l.debit), montantFEC(l.credit), "", "", date, "", "",
+		})
+	}
+	return lignes
+}
+
+// ========================================
+// FENÊTRE ÉCRITURE COMPTABLE
+// ========================================
+
+func (c *Calculatrice) fenetreEcriture() {
+	w := c.nouvelleFenetre("Écriture comptable", 850, 650)
+	decimales := c.reglages.decimales()
+
+	// Montants repris du dernier calcul de TVA, sinon valeur affichée en TTC
+	d := c.derniereTVA
+	if d == nil {
+		d = depuisTTC(c.obtenirValeurCourante(), c.reglages.tauxNormal(), decimales)
+	}
+	entreeHT := widget.NewEntry()
+	entreeHT.SetText(formaterDecimales(d.ht, decimales))
+	entreeTVA := widget.NewEntry()
+	entreeTVA.SetText(formaterDecimales(d.tva, decimales))
+	entreeTTC := widget.NewEntry()
+	entreeTTC.SetText(formaterDecimales(d.ttc, decimales))
+
+	entreeDate := widget.NewEntry()
+	entreeDate.SetText(formaterDate(aujourdhui()))
+	entreePiece := widget.NewEntry()
+	entreePiece.SetPlaceHolder("Référence de la pièce")
+	entreeLibelle := widget.NewEntry()
+	entreeLibelle.SetPlaceHolder("Libellé de l'écriture")
+
+	// Comptes du sens choisi
+	entreeJournal := widget.NewEntry()
+	entreeCompteHT, champHT := c.champCompte(w)
+	entreeCompteTVA, champTVA := c.champCompte(w)
+	entreeCompteTiers, champTiers := c.champCompte(w)
+	itemHT := widget.NewFormItem("Compte de charge", champHT)
+	itemTVA := widget.NewFormItem("TVA déductible", champTVA)
+	itemTiers := widget.NewFormItem("Fournisseur", champTiers)
+	comptes := widget.NewForm(widget.NewFormItem("Journal", entreeJournal), itemHT, itemTVA, itemTiers)
+
+	selectSens := widget.NewRadioGroup([]string{SensAchat, SensVente}, func(sens string) {
+		cpt := c.reglages.Comptes
+		if sens == SensVente {
+			entreeJournal.SetText(cpt.JournalVentes)
+			entreeCompteHT.SetText(cpt.Produit)
+			entreeCompteTVA.SetText(cpt.TVACollectee)
+			entreeCompteTiers.SetText(cpt.Client)
+			itemHT.Text, itemTVA.Text, itemTiers.Text = "Compte de produit", "TVA collectée", "Client"
+		} else {
+			entreeJournal.SetText(cpt.JournalAchats)
+			entreeCompteHT.SetText(cpt.Charge)
+			entreeCompteTVA.SetText(cpt.TVADeductible)
+			entreeCompteTiers.SetText(cpt.Fournisseur)
+			itemHT.Text, itemTVA.Text, itemTiers.Text = "Compte de charge", "TVA déductible", "Fournisseur"
+		}
+		comptes.Refresh()
+	})
+	selectSens.Horizontal = true
+	selectSens.SetSelected(SensAchat)
+
+	// Comptes saisis, sur la base des réglages
+	comptesSaisis := func() ComptesEcriture {
+		cpt := c.reglages.Comptes
+		journal := strings.TrimSpace(entreeJournal.Text)
+		ht, tva, tiers := strings.TrimSpace(entreeCompteHT.Text), strings.TrimSpace(entreeCompteTVA.Text), strings.TrimSpace(entreeCompteTiers.Text)
+		if selectSens.Selected == SensVente {
+			cpt.JournalVentes, cpt.Produit, cpt.TVACollectee, cpt.Client = journal, ht, tva, tiers
+		} else {
+			cpt.JournalAchats, cpt.Charge, cpt.TVADeductible, cpt.Fournisseur = journal, ht, tva, tiers
+		}
+		return cpt
+	}
+
+	t := &tableau{
+		titre:   "Ecriture",
+		entetes: []string{"Journal", "Date", "Pièce", "Compte", "Intitulé", "Libellé", "Débit", "Crédit"},
+	}
+	table := nouveauTableauWidget(t)
+	statut := widget.NewLabel("")
+	statut.TextStyle = fyne.TextStyle{Bold: true}
+
+	var courante *ecriture
+	generer := func() error {
+		date, err := lireDate(entreeDate.Text)
+		if err != nil {
+			return err
+		}
+		var montants decompositionTVA
+		for _, champ := range []struct {
+			entree *widget.Entry
+			valeur *float64
+			nom    string
+		}{{entreeHT, &montants.ht, "HT"}, {entreeTVA, &montants.tva, "TVA"}, {entreeTTC, &montants.ttc, "TTC"}} {
+			if *champ.valeur, err = lireNombre(champ.entree.Text); err != nil {
+				return fmt.Errorf("montant %s invalide", champ.nom)
+			}
+		}
+		cpt := comptesSaisis()
+		for _, compte := range []string{entreeJournal.Text, entreeCompteHT.Text, entreeCompteTVA.Text, entreeCompteTiers.Text} {
+			if strings.TrimSpace(compte) == "" {
+				return errors.New("journal et comptes sont obligatoires")
+			}
+		}
+
+		libelle := strings.TrimSpace(entreeLibelle.Text)
+		if libelle == "" {
+			libelle = strings.TrimSpace(selectSens.Selected + " " + entreePiece.Text)
+		}
+		e := proposerEcriture(selectSens.Selected, montants, cpt, date, strings.TrimSpace(entreePiece.Text), libelle)
+		courante = &e
+
+		t.lignes = nil
+		for _, l := range e.lignes {
+			t.lignes = append(t.lignes, []string{e.journal, formaterDate(e.date), e.piece, l.compte,
+				c.pcg().libelle(l.compte), l.libelle, formaterMontantEcriture(l.debit, decimales),
+				formaterMontantEcriture(l.credit, decimales)})
+		}
+		debit, credit := e.totaux(decimales)
+		t.lignes = append(t.lignes, []string{"Total", "", "", "", "", "",
+			formaterDecimales(debit, decimales), formaterDecimales(credit, decimales)})
+		ajusterColonnes(table, t)
+
+		if e.equilibree(decimales) {
+			statut.Importance = widget.SuccessImportance
+			statut.SetText(fmt.Sprintf("Écriture équilibrée : débit = crédit = %s", formaterDecimales(debit, decimales)))
+		} else {
+			statut.Importance = widget.DangerImportance
+			statut.SetText(fmt.Sprintf("Écriture déséquilibrée : débit %s, crédit %s, écart %s",
+				formaterDecimales(debit, decimales), formaterDecimales(credit, decimales),
+				formaterDecimales(debit-credit, decimales)))
+		}
+		return nil
+	}
+
+	btnGenerer := widget.NewButton("Générer l'écriture", func() {
+		if err := generer(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	btnGenerer.Importance = widget.HighImportance
+	btnComptes := widget.NewButton("Mémoriser les comptes", func() {
+		c.reglages.Comptes = comptesSaisis()
+		if err := c.reglages.enregistrer(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	btnFEC := widget.NewButton("Exporter FEC", func() {
+		if err := generer(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if !courante.equilibree(decimales) {
+			dialog.ShowError(errors.New("l'écriture n'est pas équilibrée"), w)
+			return
+		}
+		numero := courante.piece
+		if numero == "" {
+			numero = "1"
+		}
+		nom, err := c.nomFichierFEC(courante.date)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		c.exporterFichier(nom, ecrireFEC(courante.lignesFEC(numero, c.pcg())), w)
+	})
+
+	montants := widget.NewForm(
+		widget.NewFormItem("Montant HT", entreeHT),
+		widget.NewFormItem("TVA", entreeTVA),
+		widget.NewFormItem("Montant TTC", entreeTTC),
+		widget.NewFormItem("Date", entreeDate),
+		widget.NewFormItem("Pièce", entreePiece),
+		widget.NewFormItem("Libellé", entreeLibelle),
+	)
+	haut := container.NewVBox(
+		selectSens,
+		container.NewGridWithColumns(2, montants, comptes),
+		container.NewGridWithColumns(2, btnGenerer, btnComptes),
+		statut,
+	)
+	bas := container.NewVBox(c.boutonsExport(t, w), btnFEC)
+	generer()
+	w.SetContent(container.NewPadded(container.NewBorder(haut, bas, nil, nil, table)))
+	w.Show()
+}
+
+// Montant d'une colonne débit ou crédit (vide si nul)
+func formaterMontantEcriture(n float64, decimales int) string {
+	if n == 0 {
+		return ""
+	}
+	return formaterDecimales(n, decimales)
+}
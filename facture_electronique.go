@@ -0,0 +1,549 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// FACTURE ÉLECTRONIQUE (FACTUR-X / CII, UBL)
+// ========================================
+
+// Facture lue dans un fichier CII ou UBL, ramenée à un modèle commun
+type factureElectronique struct {
+	format, numero, devise string
+	lignes                 []ligneFactureElec
+	remisesCharges         []remiseCharge // Niveau document
+	ventilation            []ventilationTVA
+
+	totalLignes, totalRemises, totalCharges float64
+	totalHT, totalTVA, totalTTC             float64
+	acompte, arrondi, netAPayer             float64
+}
+
+type ligneFactureElec struct {
+	id, libelle    string
+	quantite       float64
+	prix           float64 // Prix net unitaire
+	baseQuantite   float64 // Quantité à laquelle s'applique le prix
+	remisesCharges float64 // Charges moins remises de ligne
+	taux           float64
+	montant        float64 // Montant HT indiqué
+}
+
+type remiseCharge struct {
+	charge  bool
+	montant float64
+	taux    float64
+}
+
+type ventilationTVA struct {
+	taux, base, tva float64
+}
+
+// Montant avec devise (TaxTotalAmount peut figurer en deux devises)
+type montantDevise struct {
+	Valeur string `xml:",chardata"`
+	Devise string `xml:"currencyID,attr"`
+}
+
+// Nombre XML (point décimal), vide = 0
+func nombreXML(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// Lecteur de nombres qui retient la première erreur
+type lecteurNombres struct{ err error }
+
+func (l *lecteurNombres) lire(s, champ string) float64 {
+	n, err := nombreXML(s)
+	if err != nil && l.err == nil {
+		l.err = fmt.Errorf("%s invalide : %q", champ, s)
+	}
+	return n
+}
+
+// ========================================
+// LECTURE CII (FACTUR-X, ZUGFERD)
+// ========================================
+
+type remiseChargeCII struct {
+	Charge  string `xml:"ChargeIndicator>Indicator"`
+	Montant string `xml:"ActualAmount"`
+	Taux    string `xml:"CategoryTradeTax>RateApplicablePercent"`
+}
+
+type factureCII struct {
+	Numero string `xml:"ExchangedDocument>ID"`
+	Lignes []struct {
+		ID             string            `xml:"AssociatedDocumentLineDocument>LineID"`
+		Nom            string            `xml:"SpecifiedTradeProduct>Name"`
+		Prix           string            `xml:"SpecifiedLineTradeAgreement>NetPriceProductTradePrice>ChargeAmount"`
+		BaseQuantite   string            `xml:"SpecifiedLineTradeAgreement>NetPriceProductTradePrice>BasisQuantity"`
+		Quantite       string            `xml:"SpecifiedLineTradeDelivery>BilledQuantity"`
+		Taux           string            `xml:"SpecifiedLineTradeSettlement>ApplicableTradeTax>RateApplicablePercent"`
+		RemisesCharges []remiseChargeCII `xml:"SpecifiedLineTradeSettlement>SpecifiedTradeAllowanceCharge"`
+		Montant        string            `xml:"SpecifiedLineTradeSettlement>SpecifiedTradeSettlementLineMonetarySummation>LineTotalAmount"`
+	} `xml:"SupplyChainTradeTransaction>IncludedSupplyChainTradeLineItem"`
+	Reglement struct {
+		Devise string `xml:"InvoiceCurrencyCode"`
+		TVA    []struct {
+			TVA  string `xml:"CalculatedAmount"`
+			Base string `xml:"BasisAmount"`
+			Taux string `xml:"RateApplicablePercent"`
+		} `xml:"ApplicableTradeTax"`
+		RemisesCharges []remiseChargeCII `xml:"SpecifiedTradeAllowanceCharge"`
+		Totaux         struct {
+			Lignes    string          `xml:"LineTotalAmount"`
+			Charges   string          `xml:"ChargeTotalAmount"`
+			Remises   string          `xml:"AllowanceTotalAmount"`
+			HT        string          `xml:"TaxBasisTotalAmount"`
+			TVA       []montantDevise `xml:"TaxTotalAmount"`
+			TTC       string          `xml:"GrandTotalAmount"`
+			Acompte   string          `xml:"TotalPrepaidAmount"`
+			Arrondi   string          `xml:"RoundingAmount"`
+			NetAPayer string          `xml:"DuePayableAmount"`
+		} `xml:"SpecifiedTradeSettlementHeaderMonetarySummation"`
+	} `xml:"SupplyChainTradeTransaction>ApplicableHeaderTradeSettlement"`
+}
+
+func lireFactureCII(donnees []byte) (*factureElectronique, error) {
+	var x factureCII
+	if err := xml.Unmarshal(donnees, &x); err != nil {
+		return nil, fmt.Errorf("XML CII invalide : %w", err)
+	}
+	var l lecteurNombres
+	r := &x.Reglement
+	f := &factureElectronique{format: "Factur-X (CII)", numero: x.Numero, devise: r.Devise}
+
+	for _, xl := range x.Lignes {
+		ligne := ligneFactureElec{
+			id:           xl.ID,
+			libelle:      xl.Nom,
+			quantite:     l.lire(xl.Quantite, "quantité"),
+			prix:         l.lire(xl.Prix, "prix net"),
+			baseQuantite: l.lire(xl.BaseQuantite, "quantité de base"),
+			taux:         l.lire(xl.Taux, "taux de TVA"),
+			montant:      l.lire(xl.Montant, "montant de ligne"),
+		}
+		for _, rc := range xl.RemisesCharges {
+			montant := l.lire(rc.Montant, "remise ou charge")
+			if rc.Charge != "true" {
+				montant = -montant
+			}
+			ligne.remisesCharges += montant
+		}
+		f.lignes = append(f.lignes, ligne)
+	}
+	for _, rc := range r.RemisesCharges {
+		f.remisesCharges = append(f.remisesCharges, remiseCharge{
+			charge:  rc.Charge == "true",
+			montant: l.lire(rc.Montant, "remise ou charge"),
+			taux:    l.lire(rc.Taux, "taux de TVA"),
+		})
+	}
+	for _, v := range r.TVA {
+		f.ventilation = append(f.ventilation, ventilationTVA{
+			taux: l.lire(v.Taux, "taux de TVA"),
+			base: l.lire(v.Base, "base de TVA"),
+			tva:  l.lire(v.TVA, "montant de TVA"),
+		})
+	}
+
+	t := &r.Totaux
+	f.totalLignes = l.lire(t.Lignes, "total des lignes")
+	f.totalCharges = l.lire(t.Charges, "total des charges")
+	f.totalRemises = l.lire(t.Remises, "total des remises")
+	f.totalHT = l.lire(t.HT, "total HT")
+	f.totalTVA = l.lire(montantDansDevise(t.TVA, f.devise), "total TVA")
+	f.totalTTC = l.lire(t.TTC, "total TTC")
+	f.acompte = l.lire(t.Acompte, "acompte")
+	f.arrondi = l.lire(t.Arrondi, "arrondi")
+	f.netAPayer = l.lire(t.NetAPayer, "net à payer")
+	return f, l.err
+}
+
+// Montant exprimé dans la devise de la facture (à défaut le premier)
+func montantDansDevise(montants []montantDevise, devise string) string {
+	for _, m := range montants {
+		if m.Devise == devise {
+			return m.Valeur
+		}
+	}
+	if len(montants) > 0 {
+		return montants[0].Valeur
+	}
+	return ""
+}
+
+// ========================================
+// LECTURE UBL (INVOICE, CREDITNOTE)
+// ========================================
+
+type remiseChargeUBL struct {
+	Charge  string `xml:"ChargeIndicator"`
+	Montant string `xml:"Amount"`
+	Taux    string `xml:"TaxCategory>Percent"`
+}
+
+type ligneUBL struct {
+	ID             string            `xml:"ID"`
+	Quantite       string            `xml:"InvoicedQuantity"`
+	QuantiteAvoir  string            `xml:"CreditedQuantity"`
+	Montant        string            `xml:"LineExtensionAmount"`
+	RemisesCharges []remiseChargeUBL `xml:"AllowanceCharge"`
+	Nom            string            `xml:"Item>Name"`
+	Taux           string            `xml:"Item>ClassifiedTaxCategory>Percent"`
+	Prix           string            `xml:"Price>PriceAmount"`
+	BaseQuantite   string            `xml:"Price>BaseQuantity"`
+}
+
+type factureUBL struct {
+	Numero         string            `xml:"ID"`
+	Devise         string            `xml:"DocumentCurrencyCode"`
+	Lignes         []ligneUBL        `xml:"InvoiceLine"`
+	LignesAvoir    []ligneUBL        `xml:"CreditNoteLine"`
+	RemisesCharges []remiseChargeUBL `xml:"AllowanceCharge"`
+	TVA            []struct {
+		Total montantDevise `xml:"TaxAmount"`
+		Sous  []struct {
+			Base string `xml:"TaxableAmount"`
+			TVA  string `xml:"TaxAmount"`
+			Taux string `xml:"TaxCategory>Percent"`
+		} `xml:"TaxSubtotal"`
+	} `xml:"TaxTotal"`
+	Totaux struct {
+		Lignes    string `xml:"LineExtensionAmount"`
+		HT        string `xml:"TaxExclusiveAmount"`
+		TTC       string `xml:"TaxInclusiveAmount"`
+		Remises   string `xml:"AllowanceTotalAmount"`
+		Charges   string `xml:"ChargeTotalAmount"`
+		Acompte   string `xml:"PrepaidAmount"`
+		Arrondi   string `xml:"PayableRoundingAmount"`
+		NetAPayer string `xml:"PayableAmount"`
+	} `xml:"LegalMonetaryTotal"`
+}
+
+func lireFactureUBL(donnees []byte) (*factureElectronique, error) {
+	var x factureUBL
+	if err := xml.Unmarshal(donnees, &x); err != nil {
+		return nil, fmt.Errorf("XML UBL invalide : %w", err)
+	}
+	var l lecteurNombres
+	f := &factureElectronique{format: "UBL", numero: x.Numero, devise: x.Devise}
+
+	for _, xl := range append(x.Lignes, x.LignesAvoir...) {
+		quantite := xl.Quantite
+		if quantite == "" {
+			quantite = xl.QuantiteAvoir
+		}
+		ligne := ligneFactureElec{
+			id:           xl.ID,
+			libelle:      xl.Nom,
+			quantite:     l.lire(quantite, "quantité"),
+			prix:         l.lire(xl.Prix, "prix net"),
+			baseQuantite: l.lire(xl.BaseQuantite, "quantité de base"),
+			taux:         l.lire(xl.Taux, "taux de TVA"),
+			montant:      l.lire(xl.Montant, "montant de ligne"),
+		}
+		for _, rc := range xl.RemisesCharges {
+			montant := l.lire(rc.Montant, "remise ou charge")
+			if rc.Charge != "true" {
+				montant = -montant
+			}
+			ligne.remisesCharges += montant
+		}
+		f.lignes = append(f.lignes, ligne)
+	}
+	for _, rc := range x.RemisesCharges {
+		f.remisesCharges = append(f.remisesCharges, remiseCharge{
+			charge:  rc.Charge == "true",
+			montant: l.lire(rc.Montant, "remise ou charge"),
+			taux:    l.lire(rc.Taux, "taux de TVA"),
+		})
+	}
+
+	// Le TaxTotal ventilé est celui de la devise de la facture
+	for _, tt := range x.TVA {
+		if len(tt.Sous) == 0 || (tt.Total.Devise != "" && tt.Total.Devise != f.devise) {
+			continue
+		}
+		f.totalTVA = l.lire(tt.Total.Valeur, "total TVA")
+		for _, v := range tt.Sous {
+			f.ventilation = append(f.ventilation, ventilationTVA{
+				taux: l.lire(v.Taux, "taux de TVA"),
+				base: l.lire(v.Base, "base de TVA"),
+				tva:  l.lire(v.TVA, "montant de TVA"),
+			})
+		}
+		break
+	}
+
+	t := &x.Totaux
+	f.totalLignes = l.lire(t.Lignes, "total des lignes")
+	f.totalHT = l.lire(t.HT, "total HT")
+	f.totalTTC = l.lire(t.TTC, "total TTC")
+	f.totalRemises = l.lire(t.Remises, "total des remises")
+	f.totalCharges = l.lire(t.Charges, "total des charges")
+	f.acompte = l.lire(t.Acompte, "acompte")
+	f.arrondi = l.lire(t.Arrondi, "arrondi")
+	f.netAPayer = l.lire(t.NetAPayer, "net à payer")
+	return f, l.err
+}
+
+// ========================================
+// DÉTECTION DU FORMAT
+// ========================================
+
+// Lit un fichier XML (CII ou UBL) ou un PDF Factur-X
+func lireFactureElectronique(donnees []byte) (*factureElectronique, error) {
+	if bytes.HasPrefix(donnees, []byte("%PDF")) {
+		xmlFacture, err := extraireXMLFacturX(donnees)
+		if err != nil {
+			return nil, err
+		}
+		donnees = xmlFacture
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(donnees))
+	for {
+		jeton, err := dec.Token()
+		if err != nil {
+			return nil, errors.New("fichier XML illisible")
+		}
+		racine, ok := jeton.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch racine.Name.Local {
+		case "CrossIndustryInvoice":
+			return lireFactureCII(donnees)
+		case "Invoice", "CreditNote":
+			return lireFactureUBL(donnees)
+		}
+		return nil, fmt.Errorf("format non reconnu (élément racine %s)", racine.Name.Local)
+	}
+}
+
+// XML CII embarqué dans un PDF Factur-X : on parcourt les flux du PDF
+// (compressés ou non) jusqu'à trouver la facture.
+func extraireXMLFacturX(pdf []byte) ([]byte, error) {
+	marque := []byte("CrossIndustryInvoice")
+	for reste := pdf; ; {
+		i := bytes.Index(reste, []byte("stream"))
+		if i < 0 {
+			break
+		}
+		if i >= 3 && string(reste[i-3:i]) == "end" {
+			reste = reste[i+6:]
+			continue
+		}
+		debut := i + 6
+		if bytes.HasPrefix(reste[debut:], []byte("\r\n")) {
+			debut += 2
+		} else if bytes.HasPrefix(reste[debut:], []byte("\n")) {
+			debut++
+		}
+		fin := bytes.Index(reste[debut:], []byte("endstream"))
+		if fin < 0 {
+			break
+		}
+		flux := reste[debut : debut+fin]
+		if r, err := zlib.NewReader(bytes.NewReader(flux)); err == nil {
+			if contenu, err := io.ReadAll(r); err == nil && bytes.Contains(contenu, marque) {
+				return contenu, nil
+			}
+		} else if bytes.Contains(flux, marque) {
+			return bytes.TrimSpace(flux), nil
+		}
+		reste = reste[debut+fin+9:]
+	}
+	return nil, errors.New("aucune facture Factur-X (XML CII) trouvée dans le PDF")
+}
+
+// ========================================
+// VÉRIFICATION DES TOTAUX
+// ========================================
+
+type controleFacture struct {
+	libelle         string
+	attendu, trouve float64
+}
+
+func (ct controleFacture) ecart() float64 {
+	return ct.trouve - ct.attendu
+}
+
+// Un écart est signalé dès qu'il atteint une demi-unité de la dernière décimale
+func (ct controleFacture) correct(decimales int) bool {
+	return math.Abs(ct.ecart()) < 0.5*math.Pow(10, -float64(decimales))
+}
+
+// Recalcule lignes, ventilation par taux et totaux. Chaque contrôle part des
+// montants indiqués au niveau précédent pour qu'une erreur ne se propage pas.
+func verifierFacture(f *factureElectronique, decimales int) []controleFacture {
+	var controles []controleFacture
+	ajouter := func(libelle string, attendu, trouve float64) {
+		controles = append(controles, controleFacture{libelle, arrondir(attendu, decimales), trouve})
+	}
+
+	bases := make(map[float64]float64)
+	var sommeLignes float64
+	for _, l := range f.lignes {
+		base := l.baseQuantite
+		if base == 0 {
+			base = 1
+		}
+		libelle := fmt.Sprintf("Ligne %s", l.id)
+		if l.libelle != "" {
+			libelle += " (" + l.libelle + ")"
+		}
+		ajouter(libelle, l.quantite*l.prix/base+l.remisesCharges, l.montant)
+		sommeLignes += l.montant
+		bases[l.taux] += l.montant
+	}
+	ajouter("Somme des lignes", sommeLignes, f.totalLignes)
+
+	var remises, charges float64
+	for _, rc := range f.remisesCharges {
+		if rc.charge {
+			charges += rc.montant
+			bases[rc.taux] += rc.montant
+		} else {
+			remises += rc.montant
+			bases[rc.taux] -= rc.montant
+		}
+	}
+	if remises != 0 || f.totalRemises != 0 {
+		ajouter("Total des remises", remises, f.totalRemises)
+	}
+	if charges != 0 || f.totalCharges != 0 {
+		ajouter("Total des charges", charges, f.totalCharges)
+	}
+	ajouter("Total HT", f.totalLignes-f.totalRemises+f.totalCharges, f.totalHT)
+
+	// Ventilation par taux : base recalculée, TVA sur la base indiquée
+	indiquees := make(map[float64]ventilationTVA)
+	for _, v := range f.ventilation {
+		indiquees[v.taux] = v
+		if _, ok := bases[v.taux]; !ok {
+			bases[v.taux] = 0
+		}
+	}
+	var taux []float64
+	for t := range bases {
+		taux = append(taux, t)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(taux)))
+	var sommeTVA float64
+	for _, t := range taux {
+		v := indiquees[t]
+		ajouter(fmt.Sprintf("Base TVA %s %%", formaterCle(t)), bases[t], v.base)
+		ajouter(fmt.Sprintf("TVA %s %%", formaterCle(t)), v.base*t/100, v.tva)
+		sommeTVA += v.tva
+	}
+	ajouter("Total TVA", sommeTVA, f.totalTVA)
+	ajouter("Total TTC", f.totalHT+f.totalTVA, f.totalTTC)
+	ajouter("Net à payer", f.totalTTC-f.acompte+f.arrondi, f.netAPayer)
+	return controles
+}
+
+// ========================================
+// FENÊTRE VÉRIFICATION FACTURE ÉLECTRONIQUE
+// ========================================
+
+func (c *Calculatrice) fenetreFactureElectronique() {
+	w := c.nouvelleFenetre("Vérification de facture électronique", 900, 650)
+
+	t := &tableau{
+		titre:   "Verification_facture",
+		entetes: []string{"Contrôle", "Attendu", "Trouvé", "Écart", "Statut"},
+	}
+	table := nouveauTableauWidget(t)
+	info := widget.NewLabel("Ouvrez une facture Factur-X (PDF ou XML CII) ou UBL. Rien n'est envoyé en ligne.")
+	info.Wrapping = fyne.TextWrapWord
+	resume := widget.NewLabel("")
+	resume.TextStyle = fyne.TextStyle{Bold: true}
+	checkEcarts := widget.NewCheck("Afficher uniquement les écarts", nil)
+
+	var facture *factureElectronique
+	afficher := func() {
+		if facture == nil {
+			return
+		}
+		decimales := decimalesDevise(facture.devise)
+		controles := verifierFacture(facture, decimales)
+		t.titre = "Verification_" + facture.numero
+		t.lignes = nil
+		ecarts := 0
+		for _, ct := range controles {
+			statut := "OK"
+			if !ct.correct(decimales) {
+				statut = "ÉCART"
+				ecarts++
+			} else if checkEcarts.Checked {
+				continue
+			}
+			t.lignes = append(t.lignes, []string{ct.libelle, formaterDecimales(ct.attendu, decimales),
+				formaterDecimales(ct.trouve, decimales), formaterDecimales(ct.ecart(), decimales), statut})
+		}
+		ajusterColonnes(table, t)
+
+		info.SetText(fmt.Sprintf("%s n° %s, devise %s, %d ligne(s)", facture.format, facture.numero,
+			facture.devise, len(facture.lignes)))
+		if ecarts == 0 {
+			resume.Importance = widget.SuccessImportance
+			resume.SetText(fmt.Sprintf("%d contrôles, aucun écart", len(controles)))
+		} else {
+			resume.Importance = widget.DangerImportance
+			resume.SetText(fmt.Sprintf("%d contrôles, %d écart(s)", len(controles), ecarts))
+		}
+	}
+	checkEcarts.OnChanged = func(bool) { afficher() }
+
+	btnOuvrir := widget.NewButton("Ouvrir une facture...", func() {
+		d := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+			if err != nil || r == nil {
+				return
+			}
+			defer r.Close()
+			donnees, err := io.ReadAll(r)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			f, err := lireFactureElectronique(donnees)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			facture = f
+			afficher()
+		}, w)
+		d.SetFilter(storage.NewExtensionFileFilter([]string{".xml", ".pdf"}))
+		d.Show()
+	})
+	btnOuvrir.Importance = widget.HighImportance
+
+	haut := container.NewVBox(btnOuvrir, info, resume, checkEcarts)
+	w.SetContent(container.NewPadded(container.NewBorder(haut, c.boutonsExport(t, w), nil, nil, table)))
+	w.Show()
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// FICHIER DES ÉCRITURES COMPTABLES (FEC)
+// ========================================
+
+// Colonnes de l'article A47 A-1 du LPF (BOI-CF-IOR-60-40-20), dans l'ordre
+var ColonnesFEC = []string{
+	"JournalCode", "JournalLib", "EcritureNum", "EcritureDate", "CompteNum", "CompteLib",
+	"CompAuxNum", "CompAuxLib", "PieceRef", "PieceDate", "EcritureLib", "Debit", "Credit",
+	"EcritureLet", "DateLet", "ValidDate", "Montantdevise", "Idevise",
+}
+
+// Dates du FEC : AAAAMMJJ
+const FormatDateFEC = "20060102"
+
+// Nom du FEC (article A47 A-1 du LPF) : <SIREN>FEC<AAAAMMJJ>, date de
+// clôture de l'exercice qui contient la date de l'écriture
+func (c *Calculatrice) nomFichierFEC(date time.Time) (string, error) {
+	if c.reglages.SIREN == "" {
+		return "", errors.New("SIREN non renseigné : indiquez-le dans Calculette > Réglages")
+	}
+	cloture, err := c.reglages.dateCloture(date)
+	if err != nil {
+		return "", err
+	}
+	return c.reglages.SIREN + "FEC" + cloture.Format(FormatDateFEC) + ".txt", nil
+}
+
+// Montant du FEC : virgule décimale, sans séparateur de milliers
+func montantFEC(n float64) string {
+	return formaterDecimales(n, 2)
+}
+
+// Le séparateur et les fins de ligne ne peuvent pas figurer dans un champ
+var nettoyageFEC = strings.NewReplacer("|", " ", "\t", " ", "\r", " ", "\n", " ")
+
+// Fichier FEC délimité par des barres verticales, fin de ligne CRLF
+func ecrireFEC(lignes [][]string) []byte {
+	var b strings.Builder
+	b.WriteString(strings.Join(ColonnesFEC, "|"))
+	b.WriteString("\r\n")
+	for _, ligne := range lignes {
+		champs := make([]string, len(ColonnesFEC))
+		for i := range champs {
+			if i < len(ligne) {
+				champs[i] = nettoyageFEC.Replace(ligne[i])
+			}
+		}
+		b.WriteString(strings.Join(champs, "|"))
+		b.WriteString("\r\n")
+	}
+	return []byte(b.String())
+}
+
+// ========================================
+// LECTURE D'UN FEC
+// ========================================
+
+// Ligne utile au contrôle des totaux
+type ligneFEC struct {
+	journal, journalLib string
+	numero              string
+	compte, libelle     string
+	debit, credit       float64
+}
+
+// Caractères de l'ISO-8859-15 qui diffèrent du Latin-1
+var latin9 = map[byte]rune{0xA4: '€', 0xA6: 'Š', 0xA8: 'š', 0xB4: 'Ž', 0xB8: 'ž', 0xBC: 'Œ', 0xBD: 'œ', 0xBE: 'Ÿ'}
+
+// Le FEC peut être en UTF-8 ou en ISO-8859-15
+func decoderTexteFEC(donnees []byte) string {
+	donnees = []byte(strings.TrimPrefix(string(donnees), "\ufeff"))
+	if utf8.Valid(donnees) {
+		return string(donnees)
+	}
+	var b strings.Builder
+	for _, octet := range donnees {
+		if r, ok := latin9[octet]; ok {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(rune(octet))
+		}
+	}
+	return b.String()
+}
+
+// Lit un FEC délimité par "|" ou par tabulations. Les montants sont lus
+// dans les colonnes Debit/Credit ou, variante admise, Montant/Sens.
+func lireFEC(donnees []byte) ([]ligneFEC, error) {
+	texte := decoderTexteFEC(donnees)
+	lignesTexte := strings.Split(strings.ReplaceAll(texte, "\r\n", "\n"), "\n")
+	if len(lignesTexte) < 2 {
+		return nil, errors.New("FEC vide")
+	}
+
+	separateur := "|"
+	if strings.Count(lignesTexte[0], "\t") > strings.Count(lignesTexte[0], "|") {
+		separateur = "\t"
+	}
+	colonnes := make(map[string]int)
+	for i, nom := range strings.Split(lignesTexte[0], separateur) {
+		colonnes[strings.ToLower(strings.TrimSpace(nom))] = i
+	}
+	for _, nom := range []string{"journalcode", "ecriturenum", "comptenum"} {
+		if _, ok := colonnes[nom]; !ok {
+			return nil, fmt.Errorf("colonne %s absente de l'en-tête", nom)
+		}
+	}
+	_, avecDebit := colonnes["debit"]
+	_, avecMontant := colonnes["montant"]
+	if !avecDebit && !avecMontant {
+		return nil, errors.New("colonnes Debit/Credit ou Montant/Sens absentes de l'en-tête")
+	}
+
+	var lignes []ligneFEC
+	for n, texteLigne := range lignesTexte[1:] {
+		if strings.TrimSpace(texteLigne) == "" {
+			continue
+		}
+		champs := strings.Split(texteLigne, separateur)
+		champ := func(nom string) string {
+			if i, ok := colonnes[nom]; ok && i < len(champs) {
+				return strings.TrimSpace(champs[i])
+			}
+			return ""
+		}
+		montant := func(nom string) (float64, error) {
+			if v := champ(nom); v != "" {
+				m, err := lireNombre(v)
+				if err != nil {
+					return 0, fmt.Errorf("ligne %d : %s invalide (%s)", n+2, nom, v)
+				}
+				return m, nil
+			}
+			return 0, nil
+		}
+
+		l := ligneFEC{
+			journal:    champ("journalcode"),
+			journalLib: champ("journallib"),
+			numero:     champ("ecriturenum"),
+			compte:     champ("comptenum"),
+			libelle:    champ("ecriturelib"),
+		}
+		var err error
+		if avecDebit {
+			if l.debit, err = montant("debit"); err != nil {
+				return nil, err
+			}
+			if l.credit, err = montant("credit"); err != nil {
+				return nil, err
+			}
+		} else {
+			m, err := montant("montant")
+			if err != nil {
+				return nil, err
+			}
+			switch strings.ToUpper(champ("sens")) {
+			case "D", "+1", "1":
+				l.debit = m
+			case "C", "-1":
+				l.credit = m
+			default:
+				return nil, fmt.Errorf("ligne %d : sens invalide (%s)", n+2, champ("sens"))
+			}
+		}
+		lignes = append(lignes, l)
+	}
+	if len(lignes) == 0 {
+		return nil, errors.New("aucune écriture dans le FEC")
+	}
+	return lignes, nil
+}
+
+// ========================================
+// TOTAUX DE CONTRÔLE
+// ========================================
+
+// Cumul par journal, par classe ou par écriture
+type cumulFEC struct {
+	code, libelle string
+	lignes        int
+	debit, credit float64
+}
+
+func (cu cumulFEC) solde() float64 {
+	return cu.debit - cu.credit
+}
+
+type controleFEC struct {
+	journaux, classes []cumulFEC
+	desequilibrees    []cumulFEC // Écritures (journal + EcritureNum) non soldées
+	total             cumulFEC
+}
+
+func controlerFEC(lignes []ligneFEC, decimales int) controleFEC {
+	journaux := make(map[string]*cumulFEC)
+	classes := make(map[string]*cumulFEC)
+	ecritures := make(map[string]*cumulFEC)
+	var ordreEcritures []string
+
+	cumuler := func(m map[string]*cumulFEC, code, libelle string, l ligneFEC) *cumulFEC {
+		cu, ok := m[code]
+		if !ok {
+			cu = &cumulFEC{code: code, libelle: libelle}
+			m[code] = cu
+		}
+		cu.lignes++
+		cu.debit += l.debit
+		cu.credit += l.credit
+		return cu
+	}
+
+	var ctrl controleFEC
+	ctrl.total.code = "Total"
+	for _, l := range lignes {
+		cumuler(journaux, l.journal, l.journalLib, l)
+		classe := "?"
+		if l.compte != "" {
+			classe = l.compte[:1]
+		}
+		cumuler(classes, classe, ClassesPCG[classe], l)
+		cle := l.journal + " / " + l.numero
+		if _, ok := ecritures[cle]; !ok {
+			ordreEcritures = append(ordreEcritures, cle)
+		}
+		cumuler(ecritures, cle, l.libelle, l)
+		ctrl.total.lignes++
+		ctrl.total.debit += l.debit
+		ctrl.total.credit += l.credit
+	}
+
+	trier := func(m map[string]*cumulFEC) []cumulFEC {
+		var liste []cumulFEC
+		for _, cu := range m {
+			liste = append(liste, *cu)
+		}
+		sort.Slice(liste, func(i, j int) bool { return liste[i].code < liste[j].code })
+		return liste
+	}
+	ctrl.journaux = trier(journaux)
+	ctrl.classes = trier(classes)
+	for _, cle := range ordreEcritures {
+		if cu := ecritures[cle]; arrondir(cu.solde(), decimales) != 0 {
+			ctrl.desequilibrees = append(ctrl.desequilibrees, *cu)
+		}
+	}
+	return ctrl
+}
+
+// ========================================
+// FENÊTRE CONTRÔLE D'UN FEC
+// ========================================
+
+func (c *Calculatrice) fenetreFEC() {
+	w := c.nouvelleFenetre("Contrôle d'un FEC", 900, 650)
+	decimales := 2 // Montants du FEC en euros
+
+	entetes := []string{"Code", "Libellé", "Lignes", "Débit", "Crédit", "Solde"}
+	tJournaux := &tableau{titre: "FEC_journaux", entetes: entetes}
+	tClasses := &tableau{titre: "FEC_classes", entetes: entetes}
+	tEcritures := &tableau{titre: "FEC_ecritures_desequilibrees", entetes: entetes}
+
+	// Un clic sur une ligne reprend son solde dans la calculatrice
+	cumuls := make(map[*tableau][]cumulFEC)
+	tables := make(map[*tableau]*widget.Table)
+	onglet := func(t *tableau, prefixe string) fyne.CanvasObject {
+		table := nouveauTableauWidget(t)
+		tables[t] = table
+		table.OnSelected = func(id widget.TableCellID) {
+			if id.Row > 0 && id.Row <= len(cumuls[t]) {
+				cu := cumuls[t][id.Row-1]
+				c.afficherResultat(fmt.Sprintf("FEC %s %s (%s - %s)", prefixe, cu.code,
+					formaterDecimales(cu.debit, decimales), formaterDecimales(cu.credit, decimales)), arrondir(cu.solde(), decimales))
+			}
+			table.UnselectAll()
+		}
+		return container.NewBorder(nil, c.boutonsExport(t, w), nil, nil, table)
+	}
+	onglets := container.NewAppTabs(
+		container.NewTabItem("Par journal", onglet(tJournaux, "journal")),
+		container.NewTabItem("Par classe", onglet(tClasses, "classe")),
+		container.NewTabItem("Écritures déséquilibrées", onglet(tEcritures, "écriture")),
+	)
+
+	info := widget.NewLabel("Ouvrez un FEC (séparateur | ou tabulation). Un clic sur une ligne reprend son solde dans la calculatrice.")
+	info.Wrapping = fyne.TextWrapWord
+	resume := widget.NewLabel("")
+	resume.TextStyle = fyne.TextStyle{Bold: true}
+	var total cumulFEC
+
+	remplir := func(t *tableau, liste []cumulFEC, avecTotal bool) {
+		cumuls[t] = liste
+		t.lignes = nil
+		var somme cumulFEC
+		for _, cu := range liste {
+			t.lignes = append(t.lignes, []string{cu.code, cu.libelle, fmt.Sprint(cu.lignes),
+				formaterDecimales(cu.debit, decimales), formaterDecimales(cu.credit, decimales),
+				formaterDecimales(cu.solde(), decimales)})
+			somme.lignes += cu.lignes
+			somme.debit += cu.debit
+			somme.credit += cu.credit
+		}
+		if avecTotal {
+			t.lignes = append(t.lignes, []string{"Total", "", fmt.Sprint(somme.lignes),
+				formaterDecimales(somme.debit, decimales), formaterDecimales(somme.credit, decimales),
+				formaterDecimales(somme.solde(), decimales)})
+		}
+		ajusterColonnes(tables[t], t)
+	}
+
+	ouvrir := func(nom string, donnees []byte) {
+		lignes, err := lireFEC(donnees)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		ctrl := controlerFEC(lignes, decimales)
+		total = ctrl.total
+		remplir(tJournaux, ctrl.journaux, true)
+		remplir(tClasses, ctrl.classes, true)
+		remplir(tEcritures, ctrl.desequilibrees, false)
+
+		info.SetText(fmt.Sprintf("%s : %d lignes, %d journaux", nom, total.lignes, len(ctrl.journaux)))
+		ecart := arrondir(total.solde(), decimales)
+		if ecart == 0 && len(ctrl.desequilibrees) == 0 {
+			resume.Importance = widget.SuccessImportance
+			resume.SetText(fmt.Sprintf("FEC équilibré : débit = crédit = %s", formaterDecimales(total.debit, decimales)))
+		} else {
+			resume.Importance = widget.DangerImportance
+			resume.SetText(fmt.Sprintf("Débit %s, crédit %s, écart %s ; %d écriture(s) déséquilibrée(s)",
+				formaterDecimales(total.debit, decimales), formaterDecimales(total.credit, decimales),
+				formaterDecimales(ecart, decimales), len(ctrl.desequilibrees)))
+		}
+	}
+
+	btnOuvrir := widget.NewButton("Ouvrir un FEC...", func() {
+		d := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+			if err != nil || r == nil {
+				return
+			}
+			defer r.Close()
+			donnees, err := io.ReadAll(r)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			ouvrir(r.URI().Name(), donnees)
+		}, w)
+		d.SetFilter(storage.NewExtensionFileFilter([]string{".txt", ".csv", ".fec"}))
+		d.Show()
+	})
+	btnOuvrir.Importance = widget.HighImportance
+	btnDebit := widget.NewButton("Total débit > calculatrice", func() {
+		if total.lignes > 0 {
+			c.afficherResultat("FEC total débit", arrondir(total.debit, decimales))
+		}
+	})
+	btnCredit := widget.NewButton("Total crédit > calculatrice", func() {
+		if total.lignes > 0 {
+			c.afficherResultat("FEC total crédit", arrondir(total.credit, decimales))
+		}
+	})
+
+	haut := container.NewVBox(container.NewGridWithColumns(3, btnOuvrir, btnDebit, btnCredit), info, resume)
+	w.SetContent(container.NewPadded(container.NewBorder(haut, nil, nil, nil, onglets)))
+	w.Show()
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// IMPÔT SUR LE REVENU (QUOTIENT FAMILIAL)
+// ========================================
+
+// Impôt = barème appliqué au revenu / nombre de parts, multiplié par le
+// nombre de parts. L'avantage des demi-parts supplémentaires est plafonné
+// (impôt calculé avec 1 ou 2 parts, diminué du plafond), puis la décote
+// s'applique aux impôts modestes.
+
+const fichierBaremeIR = "bareme_ir.csv"
+
+//go:embed donnees/bareme_ir.csv
+var baremeIRDefaut []byte
+
+type trancheIR struct {
+	seuil, taux float64
+}
+
+type baremeIR struct {
+	annee             int
+	tranches          []trancheIR // Triées par seuil
+	demiPart          float64     // Plafond de l'avantage par demi-part
+	parentIsole       float64     // Plafond des deux premières demi-parts (case T)
+	decoteCelibataire float64
+	decoteCouple      float64
+	tauxDecote        float64
+	recouvrement      float64 // Impôt non mis en recouvrement en dessous
+}
+
+type baremesIR map[int]*baremeIR
+
+func lireBaremesIR(donnees []byte) (baremesIR, error) {
+	baremes := make(baremesIR)
+	for i, champs := range lireLignesCSV(donnees) {
+		erreur := func(quoi string) error {
+			return fmt.Errorf("%s, ligne %d : %s", fichierBaremeIR, i+1, quoi)
+		}
+		if len(champs) < 3 {
+			return nil, erreur("au moins 3 colonnes attendues")
+		}
+		annee, err := strconv.Atoi(champs[0])
+		if err != nil {
+			return nil, erreur("année invalide")
+		}
+		var valeurs []float64
+		for _, champ := range champs[2:] {
+			n, err := lireNombre(champ)
+			if err != nil {
+				return nil, erreur("montant invalide : " + champ)
+			}
+			valeurs = append(valeurs, n)
+		}
+		b := baremes[annee]
+		if b == nil {
+			b = &baremeIR{annee: annee}
+			baremes[annee] = b
+		}
+		nombre := map[string]int{"tranche": 2, "demi_part": 1, "parent_isole": 1, "decote": 3, "recouvrement": 1}
+		n, connu := nombre[strings.ToLower(champs[1])]
+		if !connu {
+			return nil, erreur("type inconnu : " + champs[1])
+		}
+		if len(valeurs) < n {
+			return nil, erreur(fmt.Sprintf("%d valeur(s) attendue(s) pour %s", n, champs[1]))
+		}
+		switch strings.ToLower(champs[1]) {
+		case "tranche":
+			b.tranches = append(b.tranches, trancheIR{valeurs[0], valeurs[1]})
+		case "demi_part":
+			b.demiPart = valeurs[0]
+		case "parent_isole":
+			b.parentIsole = valeurs[0]
+		case "decote":
+			b.decoteCelibataire, b.decoteCouple, b.tauxDecote = valeurs[0], valeurs[1], valeurs[2]
+		case "recouvrement":
+			b.recouvrement = valeurs[0]
+		}
+	}
+	if len(baremes) == 0 {
+		return nil, fmt.Errorf("%s : barème vide", fichierBaremeIR)
+	}
+	for annee, b := range baremes {
+		if len(b.tranches) == 0 {
+			return nil, fmt.Errorf("%s : aucune tranche pour %d", fichierBaremeIR, annee)
+		}
+		sort.Slice(b.tranches, func(i, j int) bool { return b.tranches[i].seuil < b.tranches[j].seuil })
+	}
+	return baremes, nil
+}
+
+func chargerBaremesIR() (baremesIR, error) {
+	donnees, err := lireTableConfig(fichierBaremeIR, baremeIRDefaut)
+	if err != nil {
+		return nil, err
+	}
+	return lireBaremesIR(donnees)
+}
+
+// Années disponibles, la plus récente en premier
+func (b baremesIR) annees() []string {
+	var annees []int
+	for a := range b {
+		annees = append(annees, a)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(annees)))
+	var libelles []string
+	for _, a := range annees {
+		libelles = append(libelles, strconv.Itoa(a))
+	}
+	return libelles
+}
+
+// Barème de l'année, ou à défaut de la dernière année antérieure connue
+func (b baremesIR) pourAnnee(annee int) *baremeIR {
+	var trouve *baremeIR
+	for a, bareme := range b {
+		if a <= annee && (trouve == nil || a > trouve.annee) {
+			trouve = bareme
+		}
+	}
+	return trouve
+}
+
+// Impôt d'une part par tranche pour un quotient familial
+type ligneTrancheIR struct {
+	trancheIR
+	plafond float64 // 0 pour la dernière tranche
+	montant float64 // Part du quotient dans la tranche
+	impot   float64
+}
+
+func (b *baremeIR) parTranche(quotient float64) (lignes []ligneTrancheIR, total float64) {
+	for i, tr := range b.tranches {
+		if quotient <= tr.seuil {
+			break
+		}
+		l := ligneTrancheIR{trancheIR: tr, montant: quotient - tr.seuil}
+		if i+1 < len(b.tranches) {
+			l.plafond = b.tranches[i+1].seuil
+			l.montant = math.Min(quotient, l.plafond) - tr.seuil
+		}
+		l.impot = l.montant * tr.taux / 100
+		lignes = append(lignes, l)
+		total += l.impot
+	}
+	return lignes, total
+}
+
+// Taux de la tranche où se situe le quotient
+func (b *baremeIR) tauxMarginal(quotient float64) float64 {
+	var taux float64
+	for _, tr := range b.tranches {
+		if quotient > tr.seuil {
+			taux = tr.taux
+		}
+	}
+	return taux
+}
+
+// Impôt arrondi à l'euro pour un revenu et un nombre de parts
+func (b *baremeIR) impotParts(revenu, parts float64) float64 {
+	_, impot := b.parTranche(revenu / parts)
+	return arrondir(impot*parts, 0)
+}
+
+type calculIR struct {
+	revenu, parts, partsBase float64
+	quotient                 float64
+	tranches                 []ligneTrancheIR
+	impotParts, impotBase    float64 // Avec toutes les parts, avec 1 ou 2 parts
+	plafond                  float64 // Avantage maximal des demi-parts
+	plafonne                 bool
+	impotBrut                float64
+	decote                   float64
+	impotNet                 float64
+	recouvre                 bool
+	tauxMoyen, tauxMarginal  float64
+}
+
+func (b *baremeIR) calculer(revenu, parts float64, couple, parentIsole bool) calculIR {
+	r := calculIR{revenu: revenu, parts: parts, partsBase: 1, quotient: revenu / parts}
+	if couple {
+		r.partsBase = 2
+	}
+	r.tranches, _ = b.parTranche(r.quotient)
+	r.impotParts = b.impotParts(revenu, parts)
+	r.impotBrut = r.impotParts
+	r.tauxMarginal = b.tauxMarginal(r.quotient)
+
+	if demiParts := (parts - r.partsBase) * 2; demiParts > 0 {
+		if parentIsole && !couple && demiParts >= 2 {
+			r.plafond = b.parentIsole + (demiParts-2)*b.demiPart
+		} else {
+			r.plafond = demiParts * b.demiPart
+		}
+		r.impotBase = b.impotParts(revenu, r.partsBase)
+		if plafonne := arrondir(r.impotBase-r.plafond, 0); plafonne > r.impotParts {
+			r.plafonne = true
+			r.impotBrut = plafonne
+			r.tauxMarginal = b.tauxMarginal(revenu / r.partsBase)
+		}
+	}
+
+	forfait := b.decoteCelibataire
+	if couple {
+		forfait = b.decoteCouple
+	}
+	if d := arrondir(forfait-r.impotBrut*b.tauxDecote/100, 0); d > 0 {
+		r.decote = math.Min(d, r.impotBrut)
+	}
+	r.impotNet = r.impotBrut - r.decote
+	r.recouvre = r.impotNet >= b.recouvrement
+	if revenu > 0 {
+		r.tauxMoyen = r.impotNet / revenu * 100
+	}
+	return r
+}
+
+// ========================================
+// FENÊTRE IMPÔT SUR LE REVENU
+// ========================================
+
+func (c *Calculatrice) fenetreImpotRevenu() {
+	baremes, err := chargerBaremesIR()
+	if err != nil {
+		dialog.ShowError(err, c.fenetre)
+		return
+	}
+	w := c.nouvelleFenetre("Impôt sur le revenu", 800, 700)
+
+	selectAnnee := widget.NewSelect(baremes.annees(), nil)
+	selectAnnee.SetSelectedIndex(0)
+	entreeRevenu := widget.NewEntry()
+	entreeRevenu.SetPlaceHolder("Revenu net imposable du foyer")
+	if v := c.obtenirValeurCourante(); v > 0 {
+		entreeRevenu.SetText(c.formaterNombre(c.valeurCourante))
+	}
+	entreeParts := widget.NewEntry()
+	entreeParts.SetText("1")
+	checkCouple := widget.NewCheck("Couple marié ou pacsé (imposition commune)", func(couple bool) {
+		if parts, err := lireNombre(entreeParts.Text); err == nil && couple && parts < 2 {
+			entreeParts.SetText("2")
+		}
+	})
+	checkParentIsole := widget.NewCheck("Parent isolé (case T)", nil)
+
+	resultat := widget.NewLabel("")
+	resultat.TextStyle = fyne.TextStyle{Bold: true}
+	resultat.Wrapping = fyne.TextWrapWord
+	t := &tableau{titre: "Impot_revenu", entetes: []string{"Étape", "Détail", "Montant"}}
+	table := nouveauTableauWidget(t)
+
+	calculer := func() error {
+		annee, _ := strconv.Atoi(selectAnnee.Selected)
+		b := baremes[annee]
+		revenu, err := lireNombre(entreeRevenu.Text)
+		if err != nil || revenu < 0 {
+			return errors.New("revenu imposable invalide")
+		}
+		parts, err := lireNombre(entreeParts.Text)
+		if err != nil || parts <= 0 || parts*4 != math.Trunc(parts*4) {
+			return errors.New("nombre de parts invalide (multiple de 0,25)")
+		}
+		if checkCouple.Checked && parts < 2 {
+			return errors.New("un couple a au moins 2 parts")
+		}
+		revenu = arrondir(revenu, 0)
+		r := b.calculer(revenu, parts, checkCouple.Checked, checkParentIsole.Checked)
+
+		e := func(n float64) string { return formaterDecimales(n, 0) }
+		pct := func(n float64) string { return formaterDecimales(n, 2) + " %" }
+		t.lignes = [][]string{
+			{"Revenu net imposable", "", e(r.revenu)},
+			{"Quotient familial", fmt.Sprintf("%s / %s part(s)", e(r.revenu), formaterCle(r.parts)), formaterDecimales(r.quotient, 2)},
+		}
+		for _, l := range r.tranches {
+			tranche := fmt.Sprintf("Au-delà de %s", e(l.seuil))
+			if l.plafond > 0 {
+				tranche = fmt.Sprintf("De %s à %s", e(l.seuil), e(l.plafond))
+			}
+			t.lignes = append(t.lignes, []string{tranche,
+				fmt.Sprintf("%s × %s %%", formaterDecimales(l.montant, 2), formaterCle(l.taux)), formaterDecimales(l.impot, 2)})
+		}
+		t.lignes = append(t.lignes, []string{"Impôt avec " + formaterCle(r.parts) + " part(s)",
+			"Impôt d'une part × " + formaterCle(r.parts), e(r.impotParts)})
+		if r.plafond > 0 {
+			t.lignes = append(t.lignes,
+				[]string{"Impôt avec " + formaterCle(r.partsBase) + " part(s)", "Sans les demi-parts supplémentaires", e(r.impotBase)},
+				[]string{"Plafond de l'avantage", fmt.Sprintf("%s demi-part(s)", formaterCle((r.parts-r.partsBase)*2)), e(r.plafond)})
+			if r.plafonne {
+				t.lignes = append(t.lignes, []string{"Plafonnement du quotient familial",
+					fmt.Sprintf("%s - %s", e(r.impotBase), e(r.plafond)), e(r.impotBrut)})
+			} else {
+				t.lignes = append(t.lignes, []string{"Plafonnement du quotient familial", "Avantage inférieur au plafond", ""})
+			}
+		}
+		t.lignes = append(t.lignes, []string{"Impôt brut", "", e(r.impotBrut)})
+		if r.decote > 0 {
+			t.lignes = append(t.lignes, []string{"Décote",
+				fmt.Sprintf("Forfait - %s %% × %s", formaterCle(b.tauxDecote), e(r.impotBrut)), e(r.decote)})
+		}
+		t.lignes = append(t.lignes,
+			[]string{"Impôt net", "", e(r.impotNet)},
+			[]string{"Taux moyen", "Impôt net / revenu", pct(r.tauxMoyen)},
+			[]string{"Taux marginal", "Tranche du quotient", pct(r.tauxMarginal)},
+		)
+		if !r.recouvre && r.impotNet > 0 {
+			t.lignes = append(t.lignes, []string{"Non mis en recouvrement",
+				fmt.Sprintf("Impôt inférieur à %s", e(b.recouvrement)), "0"})
+		}
+		ajusterColonnes(table, t)
+
+		resume := fmt.Sprintf("Impôt net : %s   Taux moyen : %s   Taux marginal : %s",
+			e(r.impotNet), pct(r.tauxMoyen), pct(r.tauxMarginal))
+		if !r.recouvre && r.impotNet > 0 {
+			resume += "   (non mis en recouvrement)"
+		}
+		resultat.SetText(resume)
+
+		operation := fmt.Sprintf("IR revenus %d, %s sur %s part(s)", annee, e(r.revenu), formaterCle(r.parts))
+		c.ajouterHistorique(fmt.Sprintf("%s : impôt brut = %s", operation, e(r.impotBrut)))
+		if r.decote > 0 {
+			c.ajouterHistorique(fmt.Sprintf("%s : décote = %s", operation, e(r.decote)))
+		}
+		c.afficherResultat(operation+" : impôt net", r.impotNet)
+		return nil
+	}
+
+	btnCalculer := widget.NewButton("Calculer", func() {
+		if err := calculer(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	btnCalculer.Importance = widget.HighImportance
+
+	info := widget.NewLabel(fmt.Sprintf("Barèmes modifiables dans %s.", cheminConfig(fichierBaremeIR)))
+	info.TextStyle = fyne.TextStyle{Italic: true}
+	info.Wrapping = fyne.TextWrapWord
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Revenus de", selectAnnee),
+		widget.NewFormItem("Revenu imposable", entreeRevenu),
+		widget.NewFormItem("Nombre de parts", entreeParts),
+		widget.NewFormItem("", checkCouple),
+		widget.NewFormItem("", checkParentIsole),
+	)
+	haut := container.NewVBox(formulaire, btnCalculer, resultat)
+	bas := container.NewVBox(info, c.boutonsExport(t, w))
+	w.SetContent(container.NewPadded(container.NewBorder(haut, bas, nil, nil, table)))
+	w.Show()
+}
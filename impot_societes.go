@@ -0,0 +1,315 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// IMPÔT SUR LES SOCIÉTÉS ET DIVIDENDES
+// ========================================
+
+// IS au taux réduit des PME jusqu'au plafond, au taux normal au-delà. Les
+// dividendes versés sur le bénéfice après IS sont soumis au prélèvement
+// forfaitaire unique (PFU : IR + prélèvements sociaux) ou, sur option, au
+// barème de l'IR après abattement (les prélèvements sociaux restent dus sur
+// le montant brut). La CSG déductible l'année suivante n'est pas prise en
+// compte.
+
+const fichierTauxIS = "taux_is.csv"
+
+//go:embed donnees/taux_is.csv
+var tauxISDefaut []byte
+
+type parametresIS struct {
+	tauxReduit, plafondReduit  float64
+	tauxNormal                 float64
+	pfuIR, prelevementsSociaux float64
+	abattement                 float64
+}
+
+type tableTauxIS map[int]*parametresIS
+
+func lireTauxIS(donnees []byte) (tableTauxIS, error) {
+	table := make(tableTauxIS)
+	vus := make(map[int]map[string]bool) // Paramètres lus par année
+	for i, champs := range lireLignesCSV(donnees) {
+		erreur := func(quoi string) error {
+			return fmt.Errorf("%s, ligne %d : %s", fichierTauxIS, i+1, quoi)
+		}
+		if len(champs) < 3 {
+			return nil, erreur("format attendu annee;parametre;valeur")
+		}
+		annee, err := strconv.Atoi(champs[0])
+		if err != nil {
+			return nil, erreur("année invalide")
+		}
+		valeur, err := lireNombre(champs[2])
+		if err != nil || valeur < 0 {
+			return nil, erreur("valeur invalide")
+		}
+		p := table[annee]
+		if p == nil {
+			p = &parametresIS{}
+			table[annee] = p
+		}
+		nom := strings.ToLower(champs[1])
+		champ := map[string]*float64{
+			"is_taux_reduit":        &p.tauxReduit,
+			"is_plafond_reduit":     &p.plafondReduit,
+			"is_taux_normal":        &p.tauxNormal,
+			"pfu_ir":                &p.pfuIR,
+			"prelevements_sociaux":  &p.prelevementsSociaux,
+			"abattement_dividendes": &p.abattement,
+		}[nom]
+		if champ == nil {
+			return nil, erreur("paramètre inconnu : " + champs[1])
+		}
+		*champ = valeur
+		if vus[annee] == nil {
+			vus[annee] = make(map[string]bool)
+		}
+		vus[annee][nom] = true
+	}
+	if len(table) == 0 {
+		return nil, fmt.Errorf("%s : aucun taux", fichierTauxIS)
+	}
+	for annee, noms := range vus {
+		if len(noms) < 6 {
+			return nil, fmt.Errorf("%s : paramètres incomplets pour %d", fichierTauxIS, annee)
+		}
+	}
+	return table, nil
+}
+
+func chargerTauxIS() (tableTauxIS, error) {
+	donnees, err := lireTableConfig(fichierTauxIS, tauxISDefaut)
+	if err != nil {
+		return nil, err
+	}
+	return lireTauxIS(donnees)
+}
+
+// Années disponibles, la plus récente en premier
+func (t tableTauxIS) annees() []string {
+	var annees []int
+	for a := range t {
+		annees = append(annees, a)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(annees)))
+	var libelles []string
+	for _, a := range annees {
+		libelles = append(libelles, strconv.Itoa(a))
+	}
+	return libelles
+}
+
+// IS arrondi à l'euro, par taux
+func (p *parametresIS) impotSocietes(resultat float64, pme bool) (reduit, normal float64) {
+	if resultat <= 0 {
+		return 0, 0
+	}
+	baseReduite := 0.0
+	if pme {
+		baseReduite = math.Min(resultat, p.plafondReduit)
+	}
+	return arrondir(baseReduite*p.tauxReduit/100, 0), arrondir((resultat-baseReduite)*p.tauxNormal/100, 0)
+}
+
+// Foyer fiscal de l'associé, pour l'option au barème
+type foyerFiscal struct {
+	revenu, parts float64 // Autres revenus imposables
+	couple        bool
+}
+
+type simulationDividendes struct {
+	resultat                        float64
+	isReduit, isNormal              float64
+	benefice                        float64 // Après IS
+	dividendes                      float64
+	prelevementsSociaux             float64
+	irPFU, netPFU                   float64
+	baseBareme, irBareme            float64 // Dividendes après abattement, IR supplémentaire
+	netBareme                       float64
+	tauxGlobalPFU, tauxGlobalBareme float64 // Prélèvements / résultat avant IS
+}
+
+// dividendes < 0 : tout le bénéfice après IS est distribué
+func simulerDividendes(p *parametresIS, ir *baremeIR, resultat float64, pme bool, dividendes float64, foyer foyerFiscal) simulationDividendes {
+	s := simulationDividendes{resultat: resultat}
+	s.isReduit, s.isNormal = p.impotSocietes(resultat, pme)
+	s.benefice = resultat - s.isReduit - s.isNormal
+	s.dividendes = math.Min(dividendes, s.benefice)
+	if dividendes < 0 {
+		s.dividendes = s.benefice
+	}
+	s.dividendes = math.Max(0, s.dividendes)
+
+	s.prelevementsSociaux = arrondir(s.dividendes*p.prelevementsSociaux/100, 0)
+	s.irPFU = arrondir(s.dividendes*p.pfuIR/100, 0)
+	s.netPFU = s.dividendes - s.prelevementsSociaux - s.irPFU
+
+	s.baseBareme = arrondir(s.dividendes*(1-p.abattement/100), 0)
+	avant := ir.calculer(foyer.revenu, foyer.parts, foyer.couple, false).impotNet
+	apres := ir.calculer(foyer.revenu+s.baseBareme, foyer.parts, foyer.couple, false).impotNet
+	s.irBareme = apres - avant
+	s.netBareme = s.dividendes - s.prelevementsSociaux - s.irBareme
+
+	if resultat > 0 {
+		is := s.isReduit + s.isNormal
+		s.tauxGlobalPFU = (is + s.prelevementsSociaux + s.irPFU) / resultat * 100
+		s.tauxGlobalBareme = (is + s.prelevementsSociaux + s.irBareme) / resultat * 100
+	}
+	return s
+}
+
+// ========================================
+// FENÊTRE IS ET DIVIDENDES
+// ========================================
+
+func (c *Calculatrice) fenetreImpotSocietes() {
+	tauxIS, err := chargerTauxIS()
+	if err != nil {
+		dialog.ShowError(err, c.fenetre)
+		return
+	}
+	baremes, err := chargerBaremesIR()
+	if err != nil {
+		dialog.ShowError(err, c.fenetre)
+		return
+	}
+	w := c.nouvelleFenetre("IS et dividendes", 850, 750)
+
+	selectAnnee := widget.NewSelect(tauxIS.annees(), nil)
+	selectAnnee.SetSelectedIndex(0)
+	entreeResultat := widget.NewEntry()
+	entreeResultat.SetPlaceHolder("Résultat fiscal avant IS")
+	if v := c.obtenirValeurCourante(); v > 0 {
+		entreeResultat.SetText(c.formaterNombre(c.valeurCourante))
+	}
+	checkPME := widget.NewCheck("PME éligible au taux réduit", nil)
+	checkPME.SetChecked(true)
+	entreeDividendes := widget.NewEntry()
+	entreeDividendes.SetPlaceHolder("Vide = tout le bénéfice après IS")
+	entreeRevenu := widget.NewEntry()
+	entreeRevenu.SetPlaceHolder("Autres revenus imposables du foyer")
+	entreeParts := widget.NewEntry()
+	entreeParts.SetText("1")
+	checkCouple := widget.NewCheck("Couple marié ou pacsé", func(couple bool) {
+		if parts, err := lireNombre(entreeParts.Text); err == nil && couple && parts < 2 {
+			entreeParts.SetText("2")
+		}
+	})
+
+	resultat := widget.NewLabel("")
+	resultat.TextStyle = fyne.TextStyle{Bold: true}
+	resultat.Wrapping = fyne.TextWrapWord
+	t := &tableau{titre: "IS_dividendes", entetes: []string{"Étape", "Détail", "PFU", "Barème"}}
+	table := nouveauTableauWidget(t)
+
+	calculer := func() error {
+		annee, _ := strconv.Atoi(selectAnnee.Selected)
+		p := tauxIS[annee]
+		ir := baremes.pourAnnee(annee)
+		if ir == nil {
+			return fmt.Errorf("pas de barème de l'impôt sur le revenu pour %d", annee)
+		}
+		montant, err := lireNombre(entreeResultat.Text)
+		if err != nil {
+			return errors.New("résultat fiscal invalide")
+		}
+		dividendes := -1.0
+		if strings.TrimSpace(entreeDividendes.Text) != "" {
+			if dividendes, err = lireNombre(entreeDividendes.Text); err != nil || dividendes < 0 {
+				return errors.New("montant des dividendes invalide")
+			}
+		}
+		foyer := foyerFiscal{parts: 1, couple: checkCouple.Checked}
+		if strings.TrimSpace(entreeRevenu.Text) != "" {
+			if foyer.revenu, err = lireNombre(entreeRevenu.Text); err != nil || foyer.revenu < 0 {
+				return errors.New("autres revenus invalides")
+			}
+		}
+		if foyer.parts, err = lireNombre(entreeParts.Text); err != nil || foyer.parts <= 0 || foyer.parts*4 != math.Trunc(foyer.parts*4) {
+			return errors.New("nombre de parts invalide (multiple de 0,25)")
+		}
+		if foyer.couple && foyer.parts < 2 {
+			return errors.New("un couple a au moins 2 parts")
+		}
+
+		s := simulerDividendes(p, ir, arrondir(montant, 0), checkPME.Checked, dividendes, foyer)
+		e := func(n float64) string { return formaterDecimales(n, 0) }
+		pct := func(n float64) string { return formaterDecimales(n, 2) + " %" }
+		commun := func(etape, detail string, n float64) []string {
+			return []string{etape, detail, e(n), e(n)}
+		}
+		t.lignes = [][]string{commun("Résultat fiscal", "", s.resultat)}
+		if s.isReduit > 0 {
+			t.lignes = append(t.lignes, commun("IS au taux réduit",
+				fmt.Sprintf("%s %% jusqu'à %s", formaterCle(p.tauxReduit), e(p.plafondReduit)), s.isReduit))
+		}
+		t.lignes = append(t.lignes,
+			commun("IS au taux normal", formaterCle(p.tauxNormal)+" %", s.isNormal),
+			commun("Bénéfice après IS", "", s.benefice),
+			commun("Dividendes distribués", "", s.dividendes),
+			commun("Prélèvements sociaux", formaterCle(p.prelevementsSociaux)+" % du brut", s.prelevementsSociaux),
+			[]string{"Impôt sur le revenu",
+				fmt.Sprintf("PFU %s %% / barème %d sur %s (abattement %s %%)",
+					formaterCle(p.pfuIR), ir.annee, e(s.baseBareme), formaterCle(p.abattement)),
+				e(s.irPFU), e(s.irBareme)},
+			[]string{"Dividendes nets perçus", "", e(s.netPFU), e(s.netBareme)},
+			[]string{"Prélèvement global", "IS + prélèvements sociaux + IR / résultat",
+				pct(s.tauxGlobalPFU), pct(s.tauxGlobalBareme)},
+		)
+		ajusterColonnes(table, t)
+
+		option, net := "PFU", s.netPFU
+		if s.netBareme > s.netPFU {
+			option, net = "barème", s.netBareme
+		}
+		resultat.SetText(fmt.Sprintf("IS : %s   Net perçu : %s (PFU) / %s (barème)   Option la plus favorable : %s",
+			e(s.isReduit+s.isNormal), e(s.netPFU), e(s.netBareme), option))
+
+		c.ajouterHistorique(fmt.Sprintf("IS %d sur %s = %s", annee, e(s.resultat), e(s.isReduit+s.isNormal)))
+		c.ajouterHistorique(fmt.Sprintf("Dividendes %s nets PFU = %s", e(s.dividendes), e(s.netPFU)))
+		c.ajouterHistorique(fmt.Sprintf("Dividendes %s nets barème = %s", e(s.dividendes), e(s.netBareme)))
+		c.afficherResultat(fmt.Sprintf("Dividendes nets (%s) sur %s de résultat", option, e(s.resultat)), net)
+		return nil
+	}
+
+	btnCalculer := widget.NewButton("Calculer", func() {
+		if err := calculer(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	btnCalculer.Importance = widget.HighImportance
+
+	info := widget.NewLabel(fmt.Sprintf("Taux modifiables dans %s ; option au barème calculée avec %s.",
+		cheminConfig(fichierTauxIS), cheminConfig(fichierBaremeIR)))
+	info.TextStyle = fyne.TextStyle{Italic: true}
+	info.Wrapping = fyne.TextWrapWord
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Exercice", selectAnnee),
+		widget.NewFormItem("Résultat fiscal", entreeResultat),
+		widget.NewFormItem("", checkPME),
+		widget.NewFormItem("Dividendes", entreeDividendes),
+		widget.NewFormItem("Autres revenus", entreeRevenu),
+		widget.NewFormItem("Nombre de parts", entreeParts),
+		widget.NewFormItem("", checkCouple),
+	)
+	haut := container.NewVBox(formulaire, btnCalculer, resultat)
+	bas := container.NewVBox(info, c.boutonsExport(t, w))
+	w.SetContent(container.NewPadded(container.NewBorder(haut, bas, nil, nil, table)))
+	w.Show()
+}
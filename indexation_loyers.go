@@ -0,0 +1,305 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// INDEXATION DES LOYERS (IRL, ILC, ILAT)
+// ========================================
+
+// Loyer révisé = loyer × nouvel indice / indice de référence, le nouvel
+// indice étant celui du même trimestre un an plus tard. La variation peut
+// être plafonnée sur une période (table des indices).
+
+const fichierIndicesLoyers = "indices_loyers.csv"
+
+//go:embed donnees/indices_loyers.csv
+var indicesLoyersDefaut []byte
+
+// Trimestre numéroté en continu : annee*4 + (n-1)
+type trimestre int
+
+func lireTrimestre(s string) (trimestre, error) {
+	annee, t, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(s)), "-T")
+	a, err1 := strconv.Atoi(annee)
+	n, err2 := strconv.Atoi(t)
+	if !ok || err1 != nil || err2 != nil || n < 1 || n > 4 {
+		return 0, fmt.Errorf("trimestre invalide : %s (format AAAA-Tn)", s)
+	}
+	return trimestre(a*4 + n - 1), nil
+}
+
+func (t trimestre) String() string {
+	return fmt.Sprintf("T%d %d", int(t)%4+1, int(t)/4)
+}
+
+type plafondIndice struct {
+	debut, fin trimestre
+	taux       float64 // Variation maximale en %
+}
+
+type tableIndicesLoyers struct {
+	valeurs  map[string]map[trimestre]float64
+	plafonds map[string][]plafondIndice
+}
+
+func lireIndicesLoyers(donnees []byte) (*tableIndicesLoyers, error) {
+	table := &tableIndicesLoyers{
+		valeurs:  make(map[string]map[trimestre]float64),
+		plafonds: make(map[string][]plafondIndice),
+	}
+	for i, champs := range lireLignesCSV(donnees) {
+		erreur := func(quoi string) error {
+			return fmt.Errorf("%s, ligne %d : %s", fichierIndicesLoyers, i+1, quoi)
+		}
+		if strings.EqualFold(champs[0], "plafond") {
+			if len(champs) < 5 {
+				return nil, erreur("format attendu plafond;indice;premier trimestre;dernier trimestre;variation max")
+			}
+			debut, err1 := lireTrimestre(champs[2])
+			fin, err2 := lireTrimestre(champs[3])
+			taux, err3 := lireNombre(champs[4])
+			if err := errors.Join(err1, err2, err3); err != nil {
+				return nil, erreur(err.Error())
+			}
+			indice := strings.ToUpper(champs[1])
+			table.plafonds[indice] = append(table.plafonds[indice], plafondIndice{debut, fin, taux})
+			continue
+		}
+		if len(champs) < 3 {
+			return nil, erreur("format attendu indice;trimestre;valeur")
+		}
+		t, err := lireTrimestre(champs[1])
+		if err != nil {
+			return nil, erreur(err.Error())
+		}
+		valeur, err := lireNombre(champs[2])
+		if err != nil || valeur <= 0 {
+			return nil, erreur("valeur invalide")
+		}
+		indice := strings.ToUpper(champs[0])
+		if table.valeurs[indice] == nil {
+			table.valeurs[indice] = make(map[trimestre]float64)
+		}
+		table.valeurs[indice][t] = valeur
+	}
+	if len(table.valeurs) == 0 {
+		return nil, fmt.Errorf("%s : aucun indice", fichierIndicesLoyers)
+	}
+	return table, nil
+}
+
+func chargerIndicesLoyers() (*tableIndicesLoyers, error) {
+	donnees, err := lireTableConfig(fichierIndicesLoyers, indicesLoyersDefaut)
+	if err != nil {
+		return nil, err
+	}
+	return lireIndicesLoyers(donnees)
+}
+
+func (table *tableIndicesLoyers) indices() []string {
+	var indices []string
+	for indice := range table.valeurs {
+		indices = append(indices, indice)
+	}
+	sort.Strings(indices)
+	return indices
+}
+
+// Trimestres publiés d'un indice, le plus récent en premier
+func (table *tableIndicesLoyers) trimestres(indice string) []trimestre {
+	var liste []trimestre
+	for t := range table.valeurs[indice] {
+		liste = append(liste, t)
+	}
+	sort.Slice(liste, func(i, j int) bool { return liste[i] > liste[j] })
+	return liste
+}
+
+// Variation maximale applicable au nouvel indice (0 = pas de plafond)
+func (table *tableIndicesLoyers) plafond(indice string, t trimestre) float64 {
+	for _, p := range table.plafonds[indice] {
+		if t >= p.debut && t <= p.fin {
+			return p.taux
+		}
+	}
+	return 0
+}
+
+type revisionLoyer struct {
+	ancien, nouveau             trimestre
+	indiceAncien, indiceNouveau float64
+	variation, variationRetenue float64 // En %
+	plafonnee                   bool
+	loyerAvant, loyer           float64
+}
+
+// Révisions annuelles successives à partir du trimestre de référence, tant
+// que l'indice du trimestre suivant est publié (une seule si !successives)
+func (table *tableIndicesLoyers) reviser(indice string, loyer float64, reference trimestre,
+	successives, plafonner bool, decimales int) ([]revisionLoyer, error) {
+	valeurs := table.valeurs[indice]
+	if _, ok := valeurs[reference]; !ok {
+		return nil, fmt.Errorf("%s du %s absent de la table", indice, reference)
+	}
+	var revisions []revisionLoyer
+	for ancien := reference; ; ancien += 4 {
+		nouveau := ancien + 4
+		valeur, ok := valeurs[nouveau]
+		if !ok {
+			break
+		}
+		r := revisionLoyer{ancien: ancien, nouveau: nouveau, indiceAncien: valeurs[ancien],
+			indiceNouveau: valeur, loyerAvant: loyer}
+		r.variation = (valeur/r.indiceAncien - 1) * 100
+		r.variationRetenue = r.variation
+		if limite := table.plafond(indice, nouveau); plafonner && limite > 0 && r.variation > limite {
+			r.variationRetenue, r.plafonnee = limite, true
+		}
+		if r.plafonnee {
+			loyer = arrondir(loyer*(1+r.variationRetenue/100), decimales)
+		} else {
+			loyer = arrondir(loyer*valeur/r.indiceAncien, decimales)
+		}
+		r.loyer = loyer
+		revisions = append(revisions, r)
+		if !successives {
+			break
+		}
+	}
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("%s du %s pas encore publié", indice, reference+4)
+	}
+	return revisions, nil
+}
+
+// ========================================
+// FENÊTRE INDEXATION DES LOYERS
+// ========================================
+
+func (c *Calculatrice) fenetreIndexationLoyers() {
+	indices, err := chargerIndicesLoyers()
+	if err != nil {
+		dialog.ShowError(err, c.fenetre)
+		return
+	}
+	w := c.nouvelleFenetre("Indexation des loyers", 850, 650)
+	decimales := c.reglages.decimales()
+	f := func(n float64) string { return formaterDecimales(n, decimales) }
+
+	entreeLoyer := widget.NewEntry()
+	entreeLoyer.SetPlaceHolder("Loyer avant révision")
+	if v := c.obtenirValeurCourante(); v > 0 {
+		entreeLoyer.SetText(c.formaterNombre(c.valeurCourante))
+	}
+	checkSuccessives := widget.NewCheck("Révisions annuelles successives jusqu'au dernier indice publié", nil)
+	checkPlafond := widget.NewCheck("Appliquer les plafonnements de la table (PME pour l'ILC)", nil)
+	checkPlafond.SetChecked(true)
+	selectReference := widget.NewSelect(nil, nil)
+	var trimestres []trimestre
+	selectIndice := widget.NewSelect(indices.indices(), func(indice string) {
+		trimestres = indices.trimestres(indice)
+		var libelles []string
+		for _, t := range trimestres {
+			libelles = append(libelles, t.String())
+		}
+		// Plafonnement proposé seulement pour les indices qui en ont un
+		if len(indices.plafonds[indice]) > 0 {
+			checkPlafond.Enable()
+		} else {
+			checkPlafond.Disable()
+		}
+		selectReference.Options = libelles
+		// Par défaut, l'indice d'il y a un an : la révision porte sur le dernier publié
+		if len(libelles) > 4 {
+			selectReference.SetSelectedIndex(4)
+		} else if len(libelles) > 0 {
+			selectReference.SetSelectedIndex(len(libelles) - 1)
+		}
+		selectReference.Refresh()
+	})
+	selectIndice.SetSelectedIndex(0)
+
+	resultat := widget.NewLabel("")
+	resultat.TextStyle = fyne.TextStyle{Bold: true}
+	t := &tableau{
+		titre:   "Revision_loyer",
+		entetes: []string{"Ancien indice", "Valeur", "Nouvel indice", "Valeur", "Variation", "Retenue", "Loyer avant", "Loyer révisé"},
+	}
+	table := nouveauTableauWidget(t)
+
+	calculer := func() error {
+		loyer, err := lireNombre(entreeLoyer.Text)
+		if err != nil || loyer <= 0 {
+			return errors.New("loyer invalide")
+		}
+		if selectReference.SelectedIndex() < 0 {
+			return errors.New("choisissez le trimestre de référence")
+		}
+		indice := selectIndice.Selected
+		reference := trimestres[selectReference.SelectedIndex()]
+		revisions, err := indices.reviser(indice, arrondir(loyer, decimales), reference,
+			checkSuccessives.Checked, checkPlafond.Checked, decimales)
+		if err != nil {
+			return err
+		}
+
+		pct := func(n float64) string { return formaterDecimales(n, 2) + " %" }
+		t.lignes = nil
+		for _, r := range revisions {
+			retenue := pct(r.variationRetenue)
+			if r.plafonnee {
+				retenue += " (plafond)"
+			}
+			t.lignes = append(t.lignes, []string{r.ancien.String(), formaterCle(r.indiceAncien),
+				r.nouveau.String(), formaterCle(r.indiceNouveau), pct(r.variation), retenue,
+				f(r.loyerAvant), f(r.loyer)})
+			formule := fmt.Sprintf("%s × %s / %s", f(r.loyerAvant), formaterCle(r.indiceNouveau), formaterCle(r.indiceAncien))
+			if r.plafonnee {
+				formule = fmt.Sprintf("%s × (1 + %s %%) plafonné", f(r.loyerAvant), formaterCle(r.variationRetenue))
+			}
+			c.ajouterHistorique(fmt.Sprintf("Loyer %s %s -> %s : %s = %s", indice, r.ancien, r.nouveau, formule, f(r.loyer)))
+		}
+		ajusterColonnes(table, t)
+
+		premiere, derniere := revisions[0], revisions[len(revisions)-1]
+		resultat.SetText(fmt.Sprintf("Loyer révisé (%s %s) : %s, soit %s depuis %s",
+			indice, derniere.nouveau, f(derniere.loyer), pct((derniere.loyer/premiere.loyerAvant-1)*100), premiere.ancien))
+		c.afficherResultat(fmt.Sprintf("Loyer révisé %s %s", indice, derniere.nouveau), derniere.loyer)
+		return nil
+	}
+
+	btnCalculer := widget.NewButton("Calculer", func() {
+		if err := calculer(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	btnCalculer.Importance = widget.HighImportance
+
+	info := widget.NewLabel(fmt.Sprintf("Indices et plafonnements modifiables dans %s.", cheminConfig(fichierIndicesLoyers)))
+	info.TextStyle = fyne.TextStyle{Italic: true}
+	info.Wrapping = fyne.TextWrapWord
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Indice", selectIndice),
+		widget.NewFormItem("Loyer", entreeLoyer),
+		widget.NewFormItem("Indice de référence", selectReference),
+		widget.NewFormItem("", checkSuccessives),
+		widget.NewFormItem("", checkPlafond),
+	)
+	haut := container.NewVBox(formulaire, btnCalculer, resultat)
+	bas := container.NewVBox(info, c.boutonsExport(t, w))
+	w.SetContent(container.NewPadded(container.NewBorder(haut, bas, nil, nil, table)))
+	w.Show()
+}
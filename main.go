@@ -6,6 +6,7 @@ import (
 	"image"
 	"image/color"
 	"image/png"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -33,7 +34,8 @@ var (
 	CouleurResultat   = "#00FF88" // Couleur du résultat (vert fluo)
 )
 
-// Taux de TVA par défaut (personnalisable)
+// Taux de TVA par défaut (personnalisables ensuite dans les réglages,
+// le premier taux est le taux normal utilisé par HT>TTC et TTC>HT)
 var (
 	TauxTVAStandard = 20.0 // TVA standard France
 	TauxTVAReduit   = 10.0 // TVA réduite
@@ -57,6 +59,24 @@ type Calculatrice struct {
 	resultatAffiche  bool
 	memoireM         float64
 	fenetre          fyne.Window
+
+	// Réglages de devise et d'arrondi espèces
+	reglages *Reglages
+
+	// Conversion de devises
+	tauxChanges  *tableTaux
+	deviseSource string
+	deviseCible  string
+	infoDevise   *widget.Label
+	btnDevise    *widget.Button
+
+	btnsTVA *fyne.Container
+
+	// Dernier calcul de TVA (HT, TVA, TTC), repris par l'écriture comptable
+	derniereTVA *decompositionTVA
+
+	// Plan comptable (chargé à la première utilisation)
+	plan *planComptable
 }
 
 func main() {
@@ -73,11 +93,16 @@ func main() {
 	calc := &Calculatrice{
 		listeHistorique: make([]string, 0),
 		fenetre:         w,
+		reglages:        chargerReglages(),
 	}
 
 	// Construction de l'interface
 	contenu := calc.construireInterface()
 	w.SetContent(contenu)
+	w.SetMainMenu(calc.construireMenu())
+
+	// Taux de change hors ligne (dossier config)
+	calc.chargerTauxChanges()
 
 	// === RACCOURCIS GLOBAUX (Ctrl+C, Ctrl+V) ===
 	w.Canvas().AddShortcut(&fyne.ShortcutCopy{}, func(shortcut fyne.Shortcut) {
@@ -149,8 +174,13 @@ func (c *Calculatrice) construireInterface() fyne.CanvasObject {
 	indiceCopie.Alignment = fyne.TextAlignLeading
 	indiceCopie.TextStyle = fyne.TextStyle{Italic: true}
 
+	// Date et base des taux de change
+	c.infoDevise = widget.NewLabel("")
+	c.infoDevise.Alignment = fyne.TextAlignTrailing
+	c.infoDevise.TextStyle = fyne.TextStyle{Italic: true}
+
 	ecranContenu := container.NewVBox(
-		indiceCopie,
+		container.NewBorder(nil, nil, indiceCopie, c.infoDevise),
 		c.sousAffichage,
 		c.affichage,
 	)
@@ -169,12 +199,8 @@ func (c *Calculatrice) construireInterface() fyne.CanvasObject {
 	)
 
 	// === BOUTONS TVA (COMPTABILITÉ) ===
-	btnsTVA := container.NewGridWithColumns(4,
-		c.boutonTVA(fmt.Sprintf("%.0f%%", TauxTVAStandard), TauxTVAStandard),
-		c.boutonTVA(fmt.Sprintf("%.0f%%", TauxTVAReduit), TauxTVAReduit),
-		c.boutonTVA(fmt.Sprintf("%.1f%%", TauxTVAReduit2), TauxTVAReduit2),
-		c.boutonTVA(fmt.Sprintf("%.1f%%", TauxTVASuper), TauxTVASuper),
-	)
+	c.btnsTVA = container.NewGridWithColumns(4)
+	c.majBoutonsTVA()
 
 	// === BOUTONS FONCTIONS COMPTABLES ===
 	btnsCompta := container.NewGridWithColumns(4,
@@ -184,6 +210,22 @@ func (c *Calculatrice) construireInterface() fyne.CanvasObject {
 		c.boutonFonction("+/-", c.changerSigne),
 	)
 
+	// === BOUTONS CONVERSION FRANC / EURO ===
+	btnsDevises := container.NewGridWithColumns(3,
+		c.boutonFonction("FRF>EUR", c.francVersEuro),
+		c.boutonFonction("EUR>FRF", c.euroVersFranc),
+		c.boutonFonction("Monnaies", c.dialogueMonnaies),
+	)
+
+	// === BOUTONS DEVISES (TAUX HORS LIGNE) ===
+	c.btnDevise = c.boutonFonction("Devise", c.convertirDevise)
+	btnsChange := container.NewGridWithColumns(4,
+		c.btnDevise,
+		c.boutonFonction("Inverser", c.inverserDevises),
+		c.boutonFonction("Devises", c.dialogueDevises),
+		c.boutonFonction("Espèces", c.arrondirEnEspeces),
+	)
+
 	// === PAVÉ NUMÉRIQUE PRINCIPAL ===
 	paveNum := container.NewGridWithColumns(4,
 		// Ligne 1
@@ -252,8 +294,10 @@ func (c *Calculatrice) construireInterface() fyne.CanvasObject {
 		widget.NewSeparator(),
 		btnsMem,
 		widget.NewSeparator(),
-		btnsTVA,
+		c.btnsTVA,
 		btnsCompta,
+		btnsDevises,
+		btnsChange,
 		widget.NewSeparator(),
 		paveNum,
 	)
@@ -268,6 +312,41 @@ func (c *Calculatrice) construireInterface() fyne.CanvasObject {
 	return split
 }
 
+// Menu principal : réglages et modules
+func (c *Calculatrice) construireMenu() *fyne.MainMenu {
+	menuCalculette := fyne.NewMenu("Calculette",
+		fyne.NewMenuItem("Réglages...", c.dialogueReglages),
+	)
+	menuOutils := fyne.NewMenu("Outils",
+		fyne.NewMenuItem("Répartition au prorata...", c.fenetreRepartition),
+		fyne.NewMenuItem("Échéancier...", c.fenetreEcheancier),
+		fyne.NewMenuItem("Dates et échéances...", c.fenetreDates),
+		fyne.NewMenuItem("Pénalités de retard...", c.fenetrePenalites),
+		fyne.NewMenuItem("Barème kilométrique...", c.dialogueBaremeKm),
+		fyne.NewMenuItem("Note de frais...", c.fenetreNoteFrais),
+		fyne.NewMenuItem("Salaire brut / net...", c.fenetrePaie),
+		fyne.NewMenuItem("Impôt sur le revenu...", c.fenetreImpotRevenu),
+		fyne.NewMenuItem("IS et dividendes...", c.fenetreImpotSocietes),
+		fyne.NewMenuItem("Micro-entrepreneur...", c.fenetreMicroEntreprise),
+		fyne.NewMenuItem("Indexation des loyers...", c.fenetreIndexationLoyers),
+		fyne.NewMenuItem("Devis / facture...", c.fenetreDevis),
+		fyne.NewMenuItem("Vérifier une facture électronique...", c.fenetreFactureElectronique),
+	)
+	menuComptabilite := fyne.NewMenu("Comptabilité",
+		fyne.NewMenuItem("Écriture depuis la TVA...", c.fenetreEcriture),
+		fyne.NewMenuItem("Brouillard d'écriture...", c.fenetreBrouillard),
+		fyne.NewMenuItem("Contrôler un FEC...", c.fenetreFEC),
+		fyne.NewMenuItem("Déclaration de TVA (CA3)...", c.fenetreCA3),
+		fyne.NewMenuItem("TVA sur marge...", c.dialogueTVAMarge),
+		fyne.NewMenuItem("Autoliquidation / intracommunautaire...", c.fenetreAutoliquidation),
+		fyne.NewMenuItem("Coefficient de déduction...", c.fenetreCoefficientDeduction),
+		fyne.NewMenuItem("Fiche de stock (CMUP / FIFO)...", c.fenetreStock),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Plan comptable...", c.fenetrePlanComptable),
+	)
+	return fyne.NewMainMenu(menuCalculette, menuOutils, menuComptabilite)
+}
+
 // ========================================
 // CRÉATION DES BOUTONS
 // ========================================
@@ -324,6 +403,15 @@ func (c *Calculatrice) boutonTVA(label string, taux float64) *widget.Button {
 	return btn
 }
 
+// Reconstruit les boutons TVA à partir des taux des réglages
+func (c *Calculatrice) majBoutonsTVA() {
+	c.btnsTVA.Objects = nil
+	for _, taux := range c.reglages.TauxTVA {
+		c.btnsTVA.Add(c.boutonTVA(formaterCle(taux)+"%", taux))
+	}
+	c.btnsTVA.Refresh()
+}
+
 func (c *Calculatrice) boutonFonction(label string, action func()) *widget.Button {
 	btn := widget.NewButton(label, action)
 	btn.Importance = widget.HighImportance
@@ -432,18 +520,11 @@ func (c *Calculatrice) calculer() {
 		expression = fmt.Sprintf("%s / %s", c.formaterNombre(c.valeurPrecedente), c.formaterNombre(c.valeurCourante))
 	}
 
-	// Ajouter à l'historique
-	resultatStr := c.formaterResultat(resultat)
-	c.ajouterHistorique(fmt.Sprintf("%s = %s", expression, resultatStr))
-
-	c.valeurCourante = fmt.Sprintf("%.10f", resultat)
-	c.valeurCourante = strings.TrimRight(strings.TrimRight(c.valeurCourante, "0"), ".")
+	// Afficher (arrondi selon la devise) et ajouter à l'historique
 	c.valeurPrecedente = ""
 	c.operation = ""
-	c.resultatAffiche = true
-
-	c.sousAffichage.SetText(expression + " =")
-	c.affichage.SetText(resultatStr)
+	c.afficherResultat(expression, resultat)
+	c.sousAffichage.SetText(c.sousAffichage.Text + " =")
 }
 
 func (c *Calculatrice) effacerTout() {
@@ -484,15 +565,9 @@ func (c *Calculatrice) calculerTVA(taux float64) {
 
 	tva := valeur * taux / 100
 	expression := fmt.Sprintf("TVA %.1f%% de %s", taux, c.formaterResultat(valeur))
-	resultat := c.formaterResultat(tva)
+	c.derniereTVA = depuisHT(valeur, taux, c.reglages.decimales())
 
-	c.valeurCourante = fmt.Sprintf("%.10f", tva)
-	c.valeurCourante = strings.TrimRight(strings.TrimRight(c.valeurCourante, "0"), ".")
-	c.resultatAffiche = true
-
-	c.sousAffichage.SetText(expression)
-	c.affichage.SetText(resultat)
-	c.ajouterHistorique(fmt.Sprintf("%s = %s", expression, resultat))
+	c.afficherResultat(expression, tva)
 }
 
 func (c *Calculatrice) htVersTTC() {
@@ -501,17 +576,12 @@ func (c *Calculatrice) htVersTTC() {
 		return
 	}
 
-	ttc := valeur * (1 + TauxTVAStandard/100)
-	expression := fmt.Sprintf("%s HT > TTC (%.0f%%)", c.formaterResultat(valeur), TauxTVAStandard)
-	resultat := c.formaterResultat(ttc)
-
-	c.valeurCourante = fmt.Sprintf("%.10f", ttc)
-	c.valeurCourante = strings.TrimRight(strings.TrimRight(c.valeurCourante, "0"), ".")
-	c.resultatAffiche = true
+	taux := c.reglages.tauxNormal()
+	ttc := valeur * (1 + taux/100)
+	expression := fmt.Sprintf("%s HT > TTC (%s%%)", c.formaterResultat(valeur), formaterCle(taux))
+	c.derniereTVA = depuisHT(valeur, taux, c.reglages.decimales())
 
-	c.sousAffichage.SetText(expression)
-	c.affichage.SetText(resultat)
-	c.ajouterHistorique(fmt.Sprintf("%s = %s", expression, resultat))
+	c.afficherResultat(expression, ttc)
 }
 
 func (c *Calculatrice) ttcVersHT() {
@@ -520,17 +590,12 @@ func (c *Calculatrice) ttcVersHT() {
 		return
 	}
 
-	ht := valeur / (1 + TauxTVAStandard/100)
-	expression := fmt.Sprintf("%s TTC > HT (%.0f%%)", c.formaterResultat(valeur), TauxTVAStandard)
-	resultat := c.formaterResultat(ht)
-
-	c.valeurCourante = fmt.Sprintf("%.10f", ht)
-	c.valeurCourante = strings.TrimRight(strings.TrimRight(c.valeurCourante, "0"), ".")
-	c.resultatAffiche = true
+	taux := c.reglages.tauxNormal()
+	ht := valeur / (1 + taux/100)
+	expression := fmt.Sprintf("%s TTC > HT (%s%%)", c.formaterResultat(valeur), formaterCle(taux))
+	c.derniereTVA = depuisTTC(valeur, taux, c.reglages.decimales())
 
-	c.sousAffichage.SetText(expression)
-	c.affichage.SetText(resultat)
-	c.ajouterHistorique(fmt.Sprintf("%s = %s", expression, resultat))
+	c.afficherResultat(expression, ht)
 }
 
 func (c *Calculatrice) pourcentage() {
@@ -627,14 +692,99 @@ func (c *Calculatrice) formaterNombre(s string) string {
 	return strings.ReplaceAll(s, ".", ",")
 }
 
+// Lit un nombre saisi à la française (virgule, espaces de milliers)
+func lireNombre(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	s = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "", ",", ".").Replace(s)
+	return strconv.ParseFloat(s, 64)
+}
+
+// Formate un nombre avec un nombre fixe de décimales (virgule française)
+func formaterDecimales(n float64, decimales int) string {
+	return strings.ReplaceAll(fmt.Sprintf("%.*f", decimales, n), ".", ",")
+}
+
 func (c *Calculatrice) formaterResultat(n float64) string {
-	// Formater avec 2 décimales pour les montants, sinon intelligent
+	// Formater avec les décimales de la devise pour les montants, sinon intelligent
 	if n == float64(int64(n)) {
 		return fmt.Sprintf("%.0f", n)
 	}
 
-	resultat := fmt.Sprintf("%.2f", n)
-	return strings.ReplaceAll(resultat, ".", ",")
+	return formaterDecimales(n, c.reglages.decimales())
+}
+
+// Arrondi au plus proche (demi-unité vers l'extérieur) à n décimales.
+// Le pré-arrondi à 1e-6 absorbe les erreurs de représentation (1,005 -> 1,01).
+func arrondir(n float64, decimales int) float64 {
+	p := math.Pow10(decimales)
+	x := math.Round(n*p*1e6) / 1e6
+	return math.Round(x) / p
+}
+
+// Affiche le résultat d'une fonction, le garde comme valeur courante
+// et l'inscrit dans l'historique
+func (c *Calculatrice) afficherResultat(expression string, valeur float64) {
+	// Arrondi espèces : indiqué dans le détail et l'historique, la valeur
+	// courante reste exacte pour la suite du calcul (bouton "Espèces")
+	if c.reglages.ArrondiEspeces > 0 {
+		decimales := c.reglages.decimales()
+		montant := arrondir(valeur, decimales)
+		especes := arrondirEspeces(montant, c.reglages.ArrondiEspeces, decimales)
+		if ecart := arrondir(especes-montant, decimales); ecart != 0 {
+			expression = fmt.Sprintf("%s [espèces %s, arrondi %s%s]", expression,
+				formaterDecimales(especes, decimales), signe(ecart), formaterDecimales(ecart, decimales))
+		}
+	}
+
+	c.afficherMontant(expression, valeur, c.formaterResultat(valeur))
+}
+
+// Arrondit le montant affiché au pas espèces : montant final à encaisser
+func (c *Calculatrice) arrondirEnEspeces() {
+	if c.reglages.ArrondiEspeces <= 0 || c.valeurCourante == "" {
+		return
+	}
+	decimales := c.reglages.decimales()
+	montant := arrondir(c.obtenirValeurCourante(), decimales)
+	especes := arrondirEspeces(montant, c.reglages.ArrondiEspeces, decimales)
+	ecart := arrondir(especes-montant, decimales)
+	expression := fmt.Sprintf("Espèces %s (arrondi %s%s)", formaterDecimales(montant, decimales),
+		signe(ecart), formaterDecimales(ecart, decimales))
+	c.afficherMontant(expression, especes, c.formaterResultat(especes))
+}
+
+// Devise de travail, arrondi espèces et date des taux affichés sur l'écran
+func (c *Calculatrice) majInfoEcran() {
+	info := c.reglages.Devise
+	if c.reglages.ArrondiEspeces > 0 {
+		info += fmt.Sprintf(" (espèces %s)", libellePasEspeces(c.reglages.ArrondiEspeces))
+	}
+	if c.tauxChanges != nil {
+		info += fmt.Sprintf(" | Taux du %s (base %s)", c.tauxChanges.dateAffichee(), c.tauxChanges.base)
+	} else {
+		info += " | Pas de taux de change"
+	}
+	c.infoDevise.SetText(info)
+}
+
+// Signe explicite pour les écarts positifs
+func signe(n float64) string {
+	if n > 0 {
+		return "+"
+	}
+	return ""
+}
+
+// Variante de afficherResultat avec un résultat déjà formaté
+// (ex. montant dans une devise à 0 ou 3 décimales)
+func (c *Calculatrice) afficherMontant(expression string, valeur float64, resultat string) {
+	c.valeurCourante = fmt.Sprintf("%.10f", valeur)
+	c.valeurCourante = strings.TrimRight(strings.TrimRight(c.valeurCourante, "0"), ".")
+	c.resultatAffiche = true
+
+	c.sousAffichage.SetText(expression)
+	c.affichage.SetText(resultat)
+	c.ajouterHistorique(fmt.Sprintf("%s = %s", expression, resultat))
 }
 
 func (c *Calculatrice) ajouterHistorique(entree string) {
@@ -0,0 +1,357 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// MICRO-ENTREPRENEUR (CHARGES ET SEUILS)
+// ========================================
+
+// Cotisations, versement libératoire de l'IR et CFP sont des pourcentages
+// du chiffre d'affaires encaissé. Le suivi cumule les encaissements de
+// l'année et les compare aux seuils de la franchise en base de TVA et au
+// plafond du régime micro.
+
+const (
+	fichierMicroEntreprise = "micro_entreprise.csv"
+	fichierSuiviMicro      = "suivi_micro.csv"
+)
+
+//go:embed donnees/micro_entreprise.csv
+var microEntrepriseDefaut []byte
+
+// Part du seuil à partir de laquelle le suivi prévient
+const AlerteSeuilMicro = 90.0
+
+type activiteMicro struct {
+	libelle                       string
+	cotisations, liberatoire, cfp float64 // Taux en %
+	plafond                       float64 // Plafond du régime micro
+	franchise, franchiseMajoree   float64 // Seuils de franchise en base de TVA
+}
+
+func lireActivitesMicro(donnees []byte) ([]activiteMicro, error) {
+	var activites []activiteMicro
+	for i, champs := range lireLignesCSV(donnees) {
+		if len(champs) < 7 {
+			return nil, fmt.Errorf("%s, ligne %d : 7 colonnes attendues", fichierMicroEntreprise, i+1)
+		}
+		a := activiteMicro{libelle: champs[0]}
+		for j, v := range []*float64{&a.cotisations, &a.liberatoire, &a.cfp, &a.plafond, &a.franchise, &a.franchiseMajoree} {
+			n, err := lireNombre(champs[j+1])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%s, ligne %d : valeur invalide : %s", fichierMicroEntreprise, i+1, champs[j+1])
+			}
+			*v = n
+		}
+		activites = append(activites, a)
+	}
+	if len(activites) == 0 {
+		return nil, fmt.Errorf("%s : aucune activité", fichierMicroEntreprise)
+	}
+	return activites, nil
+}
+
+func chargerActivitesMicro() ([]activiteMicro, error) {
+	donnees, err := lireTableConfig(fichierMicroEntreprise, microEntrepriseDefaut)
+	if err != nil {
+		return nil, err
+	}
+	return lireActivitesMicro(donnees)
+}
+
+type chargesMicro struct {
+	cotisations, liberatoire, cfp float64
+	total, reste                  float64 // Reste = CA - charges
+}
+
+func (a activiteMicro) charges(ca float64, versementLiberatoire bool, decimales int) chargesMicro {
+	ch := chargesMicro{
+		cotisations: arrondir(ca*a.cotisations/100, decimales),
+		cfp:         arrondir(ca*a.cfp/100, decimales),
+	}
+	if versementLiberatoire {
+		ch.liberatoire = arrondir(ca*a.liberatoire/100, decimales)
+	}
+	ch.total = arrondir(ch.cotisations+ch.liberatoire+ch.cfp, decimales)
+	ch.reste = arrondir(ca-ch.total, decimales)
+	return ch
+}
+
+// ========================================
+// SUIVI DU CHIFFRE D'AFFAIRES
+// ========================================
+
+type encaissementMicro struct {
+	date    time.Time
+	montant float64
+}
+
+// Fichier du suivi : date;montant, dans l'ordre de saisie
+func lireSuiviMicro(donnees []byte) ([]encaissementMicro, error) {
+	var suivi []encaissementMicro
+	for i, champs := range lireLignesCSV(donnees) {
+		if len(champs) < 2 {
+			return nil, fmt.Errorf("%s, ligne %d : format attendu date;montant", fichierSuiviMicro, i+1)
+		}
+		date, err := lireDate(champs[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s, ligne %d : %w", fichierSuiviMicro, i+1, err)
+		}
+		montant, err := lireNombre(champs[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s, ligne %d : montant invalide", fichierSuiviMicro, i+1)
+		}
+		suivi = append(suivi, encaissementMicro{date, montant})
+	}
+	return suivi, nil
+}
+
+func ecrireSuiviMicro(suivi []encaissementMicro) []byte {
+	var sb strings.Builder
+	sb.WriteString("# Suivi du chiffre d'affaires micro-entrepreneur : date;montant\n")
+	for _, e := range suivi {
+		fmt.Fprintf(&sb, "%s;%s\n", formaterDate(e.date), formaterCle(e.montant))
+	}
+	return []byte(sb.String())
+}
+
+type seuilMicro struct {
+	libelle string
+	montant float64
+	effet   string // Conséquence du dépassement
+}
+
+func (a activiteMicro) seuils(annee int) []seuilMicro {
+	return []seuilMicro{
+		{"seuil de la franchise de TVA", a.franchise, fmt.Sprintf("TVA due au 1er janvier %d (dès maintenant si le seuil a aussi été dépassé en %d)", annee+1, annee-1)},
+		{"seuil majoré de la franchise de TVA", a.franchiseMajoree, "TVA due dès l'encaissement qui fait dépasser le seuil"},
+		{"plafond du régime micro", a.plafond, "régime réel si le plafond est aussi dépassé l'année suivante"},
+	}
+}
+
+// Alertes pour les encaissements d'une année (triés) : seuils dépassés,
+// dépassement prévu au rythme moyen depuis le 1er janvier, seuils proches
+func (a activiteMicro) alertes(annee int, encaissements []encaissementMicro) []string {
+	if len(encaissements) == 0 {
+		return nil
+	}
+	var alertes []string
+	for _, s := range a.seuils(annee) {
+		if s.montant <= 0 {
+			continue
+		}
+		var cumul float64
+		var depassement *time.Time
+		for i, e := range encaissements {
+			cumul += e.montant
+			if depassement == nil && cumul > s.montant {
+				depassement = &encaissements[i].date
+			}
+		}
+		switch {
+		case depassement != nil:
+			alertes = append(alertes, fmt.Sprintf("%s (%s) dépassé le %s : %s",
+				premiereMajuscule(s.libelle), formaterDecimales(s.montant, 0), formaterDate(*depassement), s.effet))
+		default:
+			dernier := encaissements[len(encaissements)-1].date
+			mois := float64(dernier.Month())
+			moyenne := cumul / mois
+			if moyenne > 0 && moyenne*12 > s.montant {
+				m := int(math.Ceil(s.montant / moyenne))
+				alertes = append(alertes, fmt.Sprintf("Au rythme actuel (%s par mois), %s (%s) sera dépassé vers %02d/%d",
+					formaterDecimales(moyenne, 0), s.libelle, formaterDecimales(s.montant, 0), m, annee))
+			} else if cumul >= s.montant*AlerteSeuilMicro/100 {
+				alertes = append(alertes, fmt.Sprintf("%s %% du %s (%s) atteint",
+					formaterDecimales(cumul/s.montant*100, 0), s.libelle, formaterDecimales(s.montant, 0)))
+			}
+		}
+	}
+	return alertes
+}
+
+func premiereMajuscule(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// ========================================
+// FENÊTRE MICRO-ENTREPRENEUR
+// ========================================
+
+func (c *Calculatrice) fenetreMicroEntreprise() {
+	activites, err := chargerActivitesMicro()
+	if err != nil {
+		dialog.ShowError(err, c.fenetre)
+		return
+	}
+	var suivi []encaissementMicro // Ordre de saisie, pour "Supprimer le dernier"
+	if donnees, err := os.ReadFile(cheminConfig(fichierSuiviMicro)); err == nil {
+		if suivi, err = lireSuiviMicro(donnees); err != nil {
+			dialog.ShowError(err, c.fenetre)
+			return
+		}
+	}
+	w := c.nouvelleFenetre("Micro-entrepreneur", 950, 750)
+	decimales := c.reglages.decimales()
+	f := func(n float64) string { return formaterDecimales(n, decimales) }
+
+	var libelles []string
+	for _, a := range activites {
+		libelles = append(libelles, a.libelle)
+	}
+	selectActivite := widget.NewSelect(libelles, nil)
+	checkLiberatoire := widget.NewCheck("Versement libératoire de l'impôt sur le revenu", nil)
+	entreeDate := widget.NewEntry()
+	entreeDate.SetText(formaterDate(aujourdhui()))
+	entreeCA := widget.NewEntry()
+	entreeCA.SetPlaceHolder("Chiffre d'affaires encaissé")
+	if v := c.obtenirValeurCourante(); v > 0 {
+		entreeCA.SetText(c.formaterNombre(c.valeurCourante))
+	}
+
+	resultat := widget.NewLabel("")
+	resultat.TextStyle = fyne.TextStyle{Bold: true}
+	alertes := widget.NewLabel("")
+	alertes.Wrapping = fyne.TextWrapWord
+	t := &tableau{
+		titre:   "Suivi_micro",
+		entetes: []string{"Date", "Encaissement", "Cumul annuel", "Cotisations", "Versement libératoire", "CFP", "Reste"},
+	}
+	table := nouveauTableauWidget(t)
+
+	activite := func() activiteMicro { return activites[selectActivite.SelectedIndex()] }
+
+	// Suivi de l'année de l'encaissement le plus récent
+	majSuivi := func() {
+		t.lignes = nil
+		alertes.SetText("")
+		if len(suivi) == 0 {
+			ajusterColonnes(table, t)
+			return
+		}
+		a := activite()
+		tries := append([]encaissementMicro(nil), suivi...)
+		sort.SliceStable(tries, func(i, j int) bool { return tries[i].date.Before(tries[j].date) })
+		annee := tries[len(tries)-1].date.Year()
+		var annuels []encaissementMicro
+		var cumul float64
+		var total chargesMicro
+		for _, e := range tries {
+			if e.date.Year() != annee {
+				continue
+			}
+			annuels = append(annuels, e)
+			cumul += e.montant
+			ch := a.charges(e.montant, checkLiberatoire.Checked, decimales)
+			total.cotisations += ch.cotisations
+			total.liberatoire += ch.liberatoire
+			total.cfp += ch.cfp
+			total.reste += ch.reste
+			t.lignes = append(t.lignes, []string{formaterDate(e.date), f(e.montant), f(cumul),
+				f(ch.cotisations), f(ch.liberatoire), f(ch.cfp), f(ch.reste)})
+		}
+		t.lignes = append(t.lignes, []string{fmt.Sprintf("Total %d", annee), f(cumul), "",
+			f(total.cotisations), f(total.liberatoire), f(total.cfp), f(total.reste)})
+		ajusterColonnes(table, t)
+
+		if messages := a.alertes(annee, annuels); len(messages) > 0 {
+			alertes.SetText(strings.Join(messages, "\n"))
+			alertes.Importance = widget.DangerImportance
+		} else {
+			alertes.SetText(fmt.Sprintf("%s encaissés en %d : seuils non atteints.", f(cumul), annee))
+			alertes.Importance = widget.SuccessImportance
+		}
+		alertes.Refresh()
+	}
+	selectActivite.OnChanged = func(string) { majSuivi() }
+	checkLiberatoire.OnChanged = func(bool) { majSuivi() }
+	selectActivite.SetSelectedIndex(0)
+
+	enregistrer := func() {
+		if err := ecrireFichierConfig(fichierSuiviMicro, ecrireSuiviMicro(suivi)); err != nil {
+			dialog.ShowError(err, w)
+		}
+		majSuivi()
+	}
+	lireCA := func() (float64, error) {
+		ca, err := lireNombre(entreeCA.Text)
+		if err != nil || ca <= 0 {
+			return 0, errors.New("chiffre d'affaires invalide")
+		}
+		return arrondir(ca, decimales), nil
+	}
+
+	btnCalculer := widget.NewButton("Calculer les charges", func() {
+		ca, err := lireCA()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		a := activite()
+		ch := a.charges(ca, checkLiberatoire.Checked, decimales)
+		resultat.SetText(fmt.Sprintf("Cotisations %s   Versement libératoire %s   CFP %s   Total %s   Reste %s",
+			f(ch.cotisations), f(ch.liberatoire), f(ch.cfp), f(ch.total), f(ch.reste)))
+		c.ajouterHistorique(fmt.Sprintf("Cotisations micro %s %% de %s = %s", formaterCle(a.cotisations), f(ca), f(ch.cotisations)))
+		if checkLiberatoire.Checked {
+			c.ajouterHistorique(fmt.Sprintf("Versement libératoire %s %% de %s = %s", formaterCle(a.liberatoire), f(ca), f(ch.liberatoire)))
+		}
+		c.ajouterHistorique(fmt.Sprintf("CFP %s %% de %s = %s", formaterCle(a.cfp), f(ca), f(ch.cfp)))
+		c.afficherResultat(fmt.Sprintf("Charges micro-entrepreneur sur %s", f(ca)), ch.total)
+	})
+	btnCalculer.Importance = widget.HighImportance
+	btnAjouter := widget.NewButton("Ajouter au suivi", func() {
+		ca, err := lireCA()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		date, err := lireDate(entreeDate.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		suivi = append(suivi, encaissementMicro{date, ca})
+		entreeCA.SetText("")
+		enregistrer()
+	})
+	// Retire le dernier encaissement saisi, quelle que soit sa date
+	btnSupprimer := widget.NewButton("Supprimer le dernier", func() {
+		if len(suivi) > 0 {
+			suivi = suivi[:len(suivi)-1]
+			enregistrer()
+		}
+	})
+
+	info := widget.NewLabel(fmt.Sprintf("Taux et seuils modifiables dans %s ; suivi enregistré dans %s.",
+		cheminConfig(fichierMicroEntreprise), cheminConfig(fichierSuiviMicro)))
+	info.TextStyle = fyne.TextStyle{Italic: true}
+	info.Wrapping = fyne.TextWrapWord
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Activité", selectActivite),
+		widget.NewFormItem("", checkLiberatoire),
+		widget.NewFormItem("Date", entreeDate),
+		widget.NewFormItem("CA encaissé", entreeCA),
+	)
+	boutons := container.NewGridWithColumns(3, btnCalculer, btnAjouter, btnSupprimer)
+	haut := container.NewVBox(formulaire, boutons, resultat, alertes)
+	bas := container.NewVBox(info, c.boutonsExport(t, w))
+	w.SetContent(container.NewPadded(container.NewBorder(haut, bas, nil, nil, table)))
+	w.Show()
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// NOTE DE FRAIS (TVA RÉCUPÉRABLE)
+// ========================================
+
+const fichierCategoriesFrais = "categories_frais.csv"
+
+//go:embed donnees/categories_frais.csv
+var categoriesFraisDefaut []byte
+
+// Catégorie de frais et part de TVA déductible
+type categorieFrais struct {
+	nom          string
+	tauxTVA      float64 // Taux proposé par défaut
+	recuperation float64 // Part de la TVA récupérable, en %
+	remarque     string
+}
+
+func lireCategoriesFrais(donnees []byte) ([]categorieFrais, error) {
+	var categories []categorieFrais
+	for i, champs := range lireLignesCSV(donnees) {
+		if len(champs) < 3 {
+			return nil, fmt.Errorf("%s, ligne %d : 3 colonnes attendues", fichierCategoriesFrais, i+1)
+		}
+		taux, err1 := lireNombre(champs[1])
+		recup, err2 := lireNombre(champs[2])
+		if err1 != nil || err2 != nil || recup < 0 || recup > 100 {
+			return nil, fmt.Errorf("%s, ligne %d : taux invalide", fichierCategoriesFrais, i+1)
+		}
+		cat := categorieFrais{nom: champs[0], tauxTVA: taux, recuperation: recup}
+		if len(champs) > 3 {
+			cat.remarque = champs[3]
+		}
+		categories = append(categories, cat)
+	}
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("%s : aucune catégorie", fichierCategoriesFrais)
+	}
+	return categories, nil
+}
+
+func chargerCategoriesFrais() ([]categorieFrais, error) {
+	donnees, err := lireTableConfig(fichierCategoriesFrais, categoriesFraisDefaut)
+	if err != nil {
+		return nil, err
+	}
+	return lireCategoriesFrais(donnees)
+}
+
+// Une dépense de la note de frais
+type ligneFrais struct {
+	date      time.Time
+	categorie categorieFrais
+	ttc       float64
+	tauxTVA   float64
+}
+
+// TVA comprise dans le TTC, part récupérable et charge non récupérable
+func (l ligneFrais) montants(decimales int) (ht, tva, recuperable, nonRecuperable float64) {
+	ht = arrondir(l.ttc/(1+l.tauxTVA/100), decimales)
+	tva = arrondir(l.ttc-ht, decimales)
+	recuperable = arrondir(tva*l.categorie.recuperation/100, decimales)
+	nonRecuperable = arrondir(l.ttc-recuperable, decimales)
+	return
+}
+
+// ========================================
+// FENÊTRE NOTE DE FRAIS
+// ========================================
+
+func (c *Calculatrice) fenetreNoteFrais() {
+	categories, err := chargerCategoriesFrais()
+	if err != nil {
+		dialog.ShowError(err, c.fenetre)
+		return
+	}
+	w := c.nouvelleFenetre("Note de frais", 1000, 650)
+
+	var noms []string
+	for _, cat := range categories {
+		noms = append(noms, cat.nom)
+	}
+	libellesTaux := append(c.reglages.libellesTauxTVA(), "0")
+
+	entreeDate := widget.NewEntry()
+	entreeDate.SetText(formaterDate(aujourdhui()))
+	entreeTTC := widget.NewEntry()
+	entreeTTC.SetPlaceHolder("Montant TTC")
+	selectTaux := widget.NewSelect(libellesTaux, nil)
+	remarque := widget.NewLabel("")
+	remarque.TextStyle = fyne.TextStyle{Italic: true}
+
+	selectCategorie := widget.NewSelect(noms, func(nom string) {
+		cat := categories[slices.Index(noms, nom)]
+		if libelle := formaterCle(cat.tauxTVA); slices.Contains(libellesTaux, libelle) {
+			selectTaux.SetSelected(libelle)
+		}
+		remarque.SetText(fmt.Sprintf("TVA récupérable : %s %%  %s", formaterCle(cat.recuperation), cat.remarque))
+	})
+	selectCategorie.SetSelectedIndex(0)
+
+	var lignes []ligneFrais
+	t := &tableau{
+		titre: "Note_de_frais",
+		entetes: []string{"Date", "Catégorie", "TTC", "Taux TVA", "HT", "TVA", "% récup.",
+			"TVA récupérable", "Non récupérable"},
+	}
+	table := nouveauTableauWidget(t)
+	resume := widget.NewLabel("")
+	resume.TextStyle = fyne.TextStyle{Bold: true}
+
+	var totalTTC, totalRecuperable, totalNonRecuperable float64
+	majTableau := func() {
+		decimales := c.reglages.decimales()
+		t.lignes = nil
+		totalTTC, totalRecuperable, totalNonRecuperable = 0, 0, 0
+		var totalHT, totalTVA float64
+		for _, l := range lignes {
+			ht, tva, recup, nonRecup := l.montants(decimales)
+			t.lignes = append(t.lignes, []string{
+				formaterDate(l.date), l.categorie.nom, formaterDecimales(l.ttc, decimales),
+				formaterCle(l.tauxTVA) + " %", formaterDecimales(ht, decimales), formaterDecimales(tva, decimales),
+				formaterCle(l.categorie.recuperation) + " %", formaterDecimales(recup, decimales),
+				formaterDecimales(nonRecup, decimales),
+			})
+			totalTTC += l.ttc
+			totalHT += ht
+			totalTVA += tva
+			totalRecuperable += recup
+			totalNonRecuperable += nonRecup
+		}
+		if len(lignes) > 0 {
+			t.lignes = append(t.lignes, []string{"Total", "", formaterDecimales(totalTTC, decimales), "",
+				formaterDecimales(totalHT, decimales), formaterDecimales(totalTVA, decimales), "",
+				formaterDecimales(totalRecuperable, decimales), formaterDecimales(totalNonRecuperable, decimales)})
+		}
+		ajusterColonnes(table, t)
+		resume.SetText(fmt.Sprintf("TVA récupérable : %s   Charge non récupérable : %s   À rembourser : %s",
+			formaterDecimales(totalRecuperable, decimales), formaterDecimales(totalNonRecuperable, decimales),
+			formaterDecimales(totalTTC, decimales)))
+	}
+	majTableau()
+
+	ajouter := func() {
+		d, err := lireDate(entreeDate.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		ttc, err := lireNombre(entreeTTC.Text)
+		if err != nil {
+			dialog.ShowError(errors.New("montant TTC invalide"), w)
+			return
+		}
+		taux, err := lireNombre(selectTaux.Selected)
+		if err != nil {
+			dialog.ShowError(errors.New("choisissez un taux de TVA"), w)
+			return
+		}
+		lignes = append(lignes, ligneFrais{
+			date:      d,
+			categorie: categories[selectCategorie.SelectedIndex()],
+			ttc:       ttc,
+			tauxTVA:   taux,
+		})
+		majTableau()
+		entreeTTC.SetText("")
+		w.Canvas().Focus(entreeTTC)
+	}
+	entreeTTC.OnSubmitted = func(string) { ajouter() }
+
+	btnAjouter := widget.NewButton("Ajouter", ajouter)
+	btnAjouter.Importance = widget.HighImportance
+	btnSupprimer := widget.NewButton("Supprimer la dernière", func() {
+		if len(lignes) > 0 {
+			lignes = lignes[:len(lignes)-1]
+			majTableau()
+		}
+	})
+	btnVider := widget.NewButton("Vider", func() {
+		lignes = nil
+		majTableau()
+	})
+	btnHistorique := widget.NewButton("Vers l'historique", func() {
+		if len(lignes) == 0 {
+			return
+		}
+		decimales := c.reglages.decimales()
+		c.ajouterHistorique(fmt.Sprintf("Note de frais (%d lignes) : TVA récupérable = %s",
+			len(lignes), formaterDecimales(totalRecuperable, decimales)))
+		c.ajouterHistorique(fmt.Sprintf("Note de frais (%d lignes) : non récupérable = %s",
+			len(lignes), formaterDecimales(totalNonRecuperable, decimales)))
+		c.afficherResultat(fmt.Sprintf("Note de frais (%d lignes) : à rembourser", len(lignes)), totalTTC)
+	})
+
+	saisie := container.NewGridWithColumns(4, entreeDate, selectCategorie, entreeTTC, selectTaux)
+	actions := container.NewGridWithColumns(4, btnAjouter, btnSupprimer, btnVider, btnHistorique)
+	haut := container.NewVBox(saisie, remarque, actions, resume)
+	w.SetContent(container.NewPadded(container.NewBorder(haut, c.boutonsExport(t, w), nil, nil, table)))
+	w.Show()
+}
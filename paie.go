@@ -0,0 +1,320 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// SALAIRE BRUT / NET (ESTIMATION DE PAIE)
+// ========================================
+
+// Estimation mensuelle : net avant impôt = brut - cotisations salariales ;
+// le prélèvement à la source s'applique au net imposable (net avant impôt +
+// CSG non déductible et CRDS) ; coût employeur = brut + cotisations patronales.
+
+const fichierCotisationsPaie = "cotisations_paie.csv"
+
+//go:embed donnees/cotisations_paie.csv
+var cotisationsPaieDefaut []byte
+
+const (
+	AbattementCSG        = 1.75 // % pour frais professionnels
+	PlafondAbattementCSG = 4.0  // En multiples du PSS
+)
+
+const (
+	SaisieBrut          = "Brut"
+	SaisieNetAvantImpot = "Net avant impôt"
+	SaisieNetPaye       = "Net payé"
+)
+
+type cotisation struct {
+	libelle            string
+	assiette           string  // "brut", "csg" ou "" pour une tranche
+	trancheMin         float64 // Tranche en multiples du PSS
+	trancheMax         float64
+	salarial, patronal float64 // Taux en %
+	imposable          bool
+}
+
+type tableCotisations struct {
+	pss         float64 // Plafond mensuel de la Sécurité sociale
+	cotisations []cotisation
+}
+
+func lireCotisationsPaie(donnees []byte) (*tableCotisations, error) {
+	t := &tableCotisations{}
+	for i, champs := range lireLignesCSV(donnees) {
+		erreur := func(quoi string) error {
+			return fmt.Errorf("%s, ligne %d : %s", fichierCotisationsPaie, i+1, quoi)
+		}
+		if strings.EqualFold(champs[0], "plafond") {
+			if len(champs) < 2 {
+				return nil, erreur("montant du plafond manquant")
+			}
+			pss, err := lireNombre(champs[1])
+			if err != nil || pss <= 0 {
+				return nil, erreur("plafond invalide")
+			}
+			t.pss = pss
+			continue
+		}
+		if len(champs) < 4 {
+			return nil, erreur("format attendu libellé;assiette;taux salarial;taux patronal;imposable")
+		}
+		cot := cotisation{libelle: champs[0]}
+		switch assiette := strings.ToLower(champs[1]); assiette {
+		case "brut", "csg":
+			cot.assiette = assiette
+		default:
+			debut, fin, ok := strings.Cut(assiette, "-")
+			var err1, err2 error
+			cot.trancheMin, err1 = lireNombre(debut)
+			cot.trancheMax, err2 = lireNombre(fin)
+			if !ok || err1 != nil || err2 != nil || cot.trancheMin >= cot.trancheMax {
+				return nil, erreur("assiette invalide (brut, csg ou tranche 0-1)")
+			}
+		}
+		var err1, err2 error
+		cot.salarial, err1 = lireNombre(champs[2])
+		cot.patronal, err2 = lireNombre(champs[3])
+		if err1 != nil || err2 != nil {
+			return nil, erreur("taux invalide")
+		}
+		cot.imposable = len(champs) > 4 && strings.EqualFold(champs[4], "oui")
+		t.cotisations = append(t.cotisations, cot)
+	}
+	if t.pss == 0 {
+		return nil, fmt.Errorf("%s : ligne plafond manquante", fichierCotisationsPaie)
+	}
+	if len(t.cotisations) == 0 {
+		return nil, fmt.Errorf("%s : aucune cotisation", fichierCotisationsPaie)
+	}
+	return t, nil
+}
+
+func chargerCotisationsPaie() (*tableCotisations, error) {
+	donnees, err := lireTableConfig(fichierCotisationsPaie, cotisationsPaieDefaut)
+	if err != nil {
+		return nil, err
+	}
+	return lireCotisationsPaie(donnees)
+}
+
+// Assiette de la cotisation pour un salaire brut
+func (cot cotisation) base(brut, pss float64) float64 {
+	switch cot.assiette {
+	case "brut":
+		return brut
+	case "csg":
+		plafond := PlafondAbattementCSG * pss
+		return math.Min(brut, plafond)*(1-AbattementCSG/100) + math.Max(0, brut-plafond)
+	}
+	debut, fin := cot.trancheMin*pss, cot.trancheMax*pss
+	return math.Max(0, math.Min(brut, fin)-debut)
+}
+
+type ligneBulletin struct {
+	cotisation
+	base, partSalariale, partPatronale float64
+}
+
+type bulletin struct {
+	brut                        float64
+	lignes                      []ligneBulletin
+	salarial, patronal          float64
+	netAvantImpot, netImposable float64
+	pas, netPaye                float64
+	coutEmployeur               float64
+}
+
+// Chaque cotisation est arrondie, comme sur un bulletin
+func (t *tableCotisations) calculerBulletin(brut, tauxPAS float64, decimales int) bulletin {
+	b := bulletin{brut: brut}
+	var imposable float64
+	for _, cot := range t.cotisations {
+		base := arrondir(cot.base(brut, t.pss), decimales)
+		l := ligneBulletin{
+			cotisation:    cot,
+			base:          base,
+			partSalariale: arrondir(base*cot.salarial/100, decimales),
+			partPatronale: arrondir(base*cot.patronal/100, decimales),
+		}
+		if l.partSalariale == 0 && l.partPatronale == 0 {
+			continue
+		}
+		b.lignes = append(b.lignes, l)
+		b.salarial += l.partSalariale
+		b.patronal += l.partPatronale
+		if cot.imposable {
+			imposable += l.partSalariale
+		}
+	}
+	b.salarial = arrondir(b.salarial, decimales)
+	b.patronal = arrondir(b.patronal, decimales)
+	b.netAvantImpot = arrondir(brut-b.salarial, decimales)
+	b.netImposable = arrondir(b.netAvantImpot+imposable, decimales)
+	b.pas = arrondir(b.netImposable*tauxPAS/100, decimales)
+	b.netPaye = arrondir(b.netAvantImpot-b.pas, decimales)
+	b.coutEmployeur = arrondir(brut+b.patronal, decimales)
+	return b
+}
+
+// Brut donnant le net voulu (avant impôt ou payé), par dichotomie : le net
+// croît avec le brut. Erreur si les taux de la table ne permettent pas de
+// l'atteindre (cotisations salariales de 100 % ou plus).
+func (t *tableCotisations) brutDepuisNet(net, tauxPAS float64, apresImpot bool, decimales int) (float64, error) {
+	obtenu := func(brut float64) float64 {
+		b := t.calculerBulletin(brut, tauxPAS, decimales)
+		if apresImpot {
+			return b.netPaye
+		}
+		return b.netAvantImpot
+	}
+	bas, haut := 0.0, net
+	for i := 0; obtenu(haut) < net; i++ {
+		if i == 60 {
+			return 0, errors.New("net inatteignable avec ces taux")
+		}
+		haut *= 2
+	}
+	for i := 0; i < 100 && haut-bas > math.Pow(10, -float64(decimales)-2); i++ {
+		milieu := (bas + haut) / 2
+		if obtenu(milieu) < net {
+			bas = milieu
+		} else {
+			haut = milieu
+		}
+	}
+	// Plus petit brut arrondi qui atteint le net
+	brut := arrondir(haut, decimales)
+	if obtenu(brut) < net {
+		brut = arrondir(brut+math.Pow(10, -float64(decimales)), decimales)
+	}
+	return brut, nil
+}
+
+// ========================================
+// FENÊTRE SALAIRE BRUT / NET
+// ========================================
+
+func (c *Calculatrice) fenetrePaie() {
+	w := c.nouvelleFenetre("Salaire brut / net", 950, 750)
+
+	selectSaisie := widget.NewSelect([]string{SaisieBrut, SaisieNetAvantImpot, SaisieNetPaye}, nil)
+	selectSaisie.SetSelected(SaisieBrut)
+	entreeMontant := widget.NewEntry()
+	entreeMontant.SetPlaceHolder("Montant mensuel")
+	if v := c.obtenirValeurCourante(); v > 0 {
+		entreeMontant.SetText(c.formaterNombre(c.valeurCourante))
+	}
+	entreePAS := widget.NewEntry()
+	entreePAS.SetText("0")
+	entreePAS.SetPlaceHolder("Taux du prélèvement à la source, %")
+
+	resultat := widget.NewLabel("")
+	resultat.TextStyle = fyne.TextStyle{Bold: true}
+	resultat.Wrapping = fyne.TextWrapWord
+	info := widget.NewLabel("")
+	info.TextStyle = fyne.TextStyle{Italic: true}
+	info.Wrapping = fyne.TextWrapWord
+	info.SetText(fmt.Sprintf("Taux modifiables dans %s (estimation hors réductions de cotisations).",
+		cheminConfig(fichierCotisationsPaie)))
+
+	t := &tableau{
+		titre:   "Bulletin_estimation",
+		entetes: []string{"Cotisation", "Base", "Taux salarial", "Part salariale", "Taux patronal", "Part patronale"},
+	}
+	table := nouveauTableauWidget(t)
+
+	calculer := func() error {
+		cotisations, err := chargerCotisationsPaie()
+		if err != nil {
+			return err
+		}
+		montant, err := lireNombre(entreeMontant.Text)
+		if err != nil || montant <= 0 {
+			return errors.New("montant invalide")
+		}
+		tauxPAS, err := lireNombre(strings.TrimSuffix(strings.TrimSpace(entreePAS.Text), "%"))
+		if err != nil || tauxPAS < 0 || tauxPAS >= 100 {
+			return errors.New("taux de prélèvement à la source invalide")
+		}
+
+		decimales := c.reglages.decimales()
+		f := func(n float64) string { return formaterDecimales(n, decimales) }
+		brut := arrondir(montant, decimales)
+		switch selectSaisie.Selected {
+		case SaisieNetAvantImpot:
+			brut, err = cotisations.brutDepuisNet(montant, tauxPAS, false, decimales)
+		case SaisieNetPaye:
+			brut, err = cotisations.brutDepuisNet(montant, tauxPAS, true, decimales)
+		}
+		if err != nil {
+			return err
+		}
+		b := cotisations.calculerBulletin(brut, tauxPAS, decimales)
+
+		taux := func(n float64) string {
+			if n == 0 {
+				return ""
+			}
+			return formaterCle(n) + " %"
+		}
+		t.lignes = nil
+		for _, l := range b.lignes {
+			t.lignes = append(t.lignes, []string{l.libelle, f(l.base), taux(l.salarial),
+				formaterMontantEcriture(l.partSalariale, decimales), taux(l.patronal),
+				formaterMontantEcriture(l.partPatronale, decimales)})
+		}
+		t.lignes = append(t.lignes,
+			[]string{"Total des cotisations", "", "", f(b.salarial), "", f(b.patronal)},
+			[]string{"Salaire brut", f(b.brut), "", "", "", ""},
+			[]string{"Net avant impôt", f(b.netAvantImpot), "", "", "", ""},
+			[]string{"Net imposable", f(b.netImposable), "", "", "", ""},
+			[]string{"Prélèvement à la source", f(b.pas), taux(tauxPAS), "", "", ""},
+			[]string{"Net payé", f(b.netPaye), "", "", "", ""},
+			[]string{"Coût employeur", f(b.coutEmployeur), "", "", "", ""},
+		)
+		ajusterColonnes(table, t)
+		resultat.SetText(fmt.Sprintf("Brut %s   Net avant impôt %s   Net payé %s   Coût employeur %s",
+			f(b.brut), f(b.netAvantImpot), f(b.netPaye), f(b.coutEmployeur)))
+
+		switch selectSaisie.Selected {
+		case SaisieBrut:
+			c.ajouterHistorique(fmt.Sprintf("Coût employeur pour %s brut = %s", f(b.brut), f(b.coutEmployeur)))
+			c.ajouterHistorique(fmt.Sprintf("Net avant impôt pour %s brut = %s", f(b.brut), f(b.netAvantImpot)))
+			c.afficherResultat(fmt.Sprintf("Net payé pour %s brut (PAS %s %%)", f(b.brut), formaterCle(tauxPAS)), b.netPaye)
+		default:
+			c.ajouterHistorique(fmt.Sprintf("Coût employeur pour %s brut = %s", f(b.brut), f(b.coutEmployeur)))
+			c.afficherResultat(fmt.Sprintf("Brut pour %s %s", f(montant), strings.ToLower(selectSaisie.Selected)), b.brut)
+		}
+		return nil
+	}
+
+	btnCalculer := widget.NewButton("Calculer", func() {
+		if err := calculer(); err != nil {
+			dialog.ShowError(err, w)
+		}
+	})
+	btnCalculer.Importance = widget.HighImportance
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Montant saisi", selectSaisie),
+		widget.NewFormItem("Montant mensuel", entreeMontant),
+		widget.NewFormItem("Taux PAS %", entreePAS),
+	)
+	haut := container.NewVBox(formulaire, btnCalculer, resultat)
+	bas := container.NewVBox(info, c.boutonsExport(t, w))
+	w.SetContent(container.NewPadded(container.NewBorder(haut, bas, nil, nil, table)))
+	w.Show()
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// PLAN COMPTABLE GÉNÉRAL (PCG)
+// ========================================
+
+const fichierPlanComptable = "plan_comptable.csv"
+
+//go:embed donnees/plan_comptable.csv
+var planComptableDefaut []byte
+
+// Nombre maximal de comptes affichés dans une recherche
+const MaxResultatsPCG = 300
+
+// Classes du plan comptable général
+var ClassesPCG = map[string]string{
+	"1": "Comptes de capitaux",
+	"2": "Comptes d'immobilisations",
+	"3": "Comptes de stocks et en-cours",
+	"4": "Comptes de tiers",
+	"5": "Comptes financiers",
+	"6": "Comptes de charges",
+	"7": "Comptes de produits",
+	"8": "Comptes spéciaux",
+	"9": "Comptabilité analytique",
+}
+
+type comptePCG struct {
+	numero, libelle string
+}
+
+type planComptable struct {
+	comptes  []comptePCG // Triés par numéro
+	libelles map[string]string
+}
+
+func lirePlanComptable(donnees []byte) (*planComptable, error) {
+	p := &planComptable{libelles: make(map[string]string)}
+	for i, champs := range lireLignesCSV(donnees) {
+		if len(champs) < 2 || champs[0] == "" {
+			return nil, fmt.Errorf("%s, ligne %d : format attendu compte;libellé", fichierPlanComptable, i+1)
+		}
+		if _, existe := p.libelles[champs[0]]; existe {
+			return nil, fmt.Errorf("%s, ligne %d : compte %s en double", fichierPlanComptable, i+1, champs[0])
+		}
+		p.comptes = append(p.comptes, comptePCG{champs[0], champs[1]})
+		p.libelles[champs[0]] = champs[1]
+	}
+	if len(p.comptes) == 0 {
+		return nil, fmt.Errorf("%s : aucun compte", fichierPlanComptable)
+	}
+	sort.Slice(p.comptes, func(i, j int) bool { return p.comptes[i].numero < p.comptes[j].numero })
+	return p, nil
+}
+
+func chargerPlanComptable() (*planComptable, error) {
+	donnees, err := lireTableConfig(fichierPlanComptable, planComptableDefaut)
+	if err != nil {
+		return nil, err
+	}
+	return lirePlanComptable(donnees)
+}
+
+// Libellé du compte ou du compte parent le plus proche (607100 -> 607)
+func (p *planComptable) libelle(numero string) string {
+	for n := numero; n != ""; n = n[:len(n)-1] {
+		if libelle, ok := p.libelles[n]; ok {
+			return libelle
+		}
+	}
+	if numero == "" {
+		return ""
+	}
+	return ClassesPCG[numero[:1]]
+}
+
+// Recherche par début de numéro ("445") ou par mots du libellé ("tva coll")
+func (p *planComptable) rechercher(requete string) []comptePCG {
+	requete = strings.TrimSpace(requete)
+	if requete == "" {
+		return p.comptes
+	}
+	var resultats []comptePCG
+	if requete[0] >= '0' && requete[0] <= '9' {
+		for _, cpt := range p.comptes {
+			if strings.HasPrefix(cpt.numero, requete) {
+				resultats = append(resultats, cpt)
+			}
+		}
+		return resultats
+	}
+
+	mots := strings.Fields(sansAccents(requete))
+	for _, cpt := range p.comptes {
+		libelle := sansAccents(cpt.libelle)
+		trouve := true
+		for _, mot := range mots {
+			if !strings.Contains(libelle, mot) {
+				trouve = false
+				break
+			}
+		}
+		if trouve {
+			resultats = append(resultats, cpt)
+		}
+	}
+	return resultats
+}
+
+var remplacementAccents = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a", "ç", "c", "é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i", "ô", "o", "ö", "o", "ù", "u", "û", "u", "ü", "u", "œ", "oe", "’", "'",
+)
+
+// Minuscules sans accents, pour la recherche
+func sansAccents(s string) string {
+	return remplacementAccents.Replace(strings.ToLower(s))
+}
+
+// Plan comptable chargé à la première utilisation (défaut intégré si le
+// fichier de config est invalide)
+func (c *Calculatrice) pcg() *planComptable {
+	if c.plan == nil {
+		if err := c.chargerPlanComptable(); err != nil {
+			dialog.ShowError(err, c.fenetre)
+		}
+	}
+	return c.plan
+}
+
+func (c *Calculatrice) chargerPlanComptable() error {
+	plan, err := chargerPlanComptable()
+	if err != nil {
+		if c.plan == nil {
+			c.plan, _ = lirePlanComptable(planComptableDefaut)
+		}
+		return err
+	}
+	c.plan = plan
+	return nil
+}
+
+// ========================================
+// RECHERCHE D'UN COMPTE
+// ========================================
+
+// Liste filtrée par la saisie ; choisir appelé au clic sur un compte
+func (c *Calculatrice) listeComptes(choisir func(comptePCG)) (*widget.Entry, *widget.List) {
+	var resultats []comptePCG
+	liste := widget.NewList(
+		func() int { return len(resultats) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(resultats[id].numero + "  " + resultats[id].libelle)
+		},
+	)
+	liste.OnSelected = func(id widget.ListItemID) {
+		liste.UnselectAll()
+		choisir(resultats[id])
+	}
+
+	recherche := widget.NewEntry()
+	recherche.SetPlaceHolder("Numéro (445...) ou mots du libellé (tva coll...)")
+	recherche.OnChanged = func(requete string) {
+		resultats = c.pcg().rechercher(requete)
+		if len(resultats) > MaxResultatsPCG {
+			resultats = resultats[:MaxResultatsPCG]
+		}
+		liste.Refresh()
+	}
+	recherche.OnChanged("")
+	return recherche, liste
+}
+
+// Champ de saisie d'un compte avec bouton de recherche dans le PCG
+func (c *Calculatrice) champCompte(parent fyne.Window) (*widget.Entry, fyne.CanvasObject) {
+	entree := widget.NewEntry()
+	btn := widget.NewButton("...", func() {
+		var d dialog.Dialog
+		recherche, liste := c.listeComptes(func(cpt comptePCG) {
+			entree.SetText(cpt.numero)
+			d.Hide()
+		})
+		recherche.SetText(entree.Text)
+		d = dialog.NewCustom("Plan comptable", "Fermer", container.NewBorder(recherche, nil, nil, nil, liste), parent)
+		d.Resize(fyne.NewSize(600, 500))
+		d.Show()
+		parent.Canvas().Focus(recherche)
+	})
+	return entree, container.NewBorder(nil, nil, nil, btn, entree)
+}
+
+// ========================================
+// FENÊTRE PLAN COMPTABLE
+// ========================================
+
+func (c *Calculatrice) fenetrePlanComptable() {
+	w := c.nouvelleFenetre("Plan comptable", 750, 650)
+
+	detail := widget.NewLabel("")
+	detail.TextStyle = fyne.TextStyle{Bold: true}
+	detail.Wrapping = fyne.TextWrapWord
+	recherche, liste := c.listeComptes(func(cpt comptePCG) {
+		detail.SetText(fmt.Sprintf("%s  %s (%s)", cpt.numero, cpt.libelle, ClassesPCG[cpt.numero[:1]]))
+		w.Clipboard().SetContent(cpt.numero)
+	})
+	info := widget.NewLabel("")
+	info.TextStyle = fyne.TextStyle{Italic: true}
+	info.Wrapping = fyne.TextWrapWord
+	majInfo := func() {
+		info.SetText(fmt.Sprintf("%d comptes, fichier %s. Un clic copie le numéro.",
+			len(c.pcg().comptes), cheminConfig(fichierPlanComptable)))
+		recherche.OnChanged(recherche.Text)
+	}
+	majInfo()
+
+	btnRecharger := widget.NewButton("Recharger", func() {
+		if err := c.chargerPlanComptable(); err != nil {
+			dialog.ShowError(err, w)
+		}
+		majInfo()
+	})
+	btnImporter := widget.NewButton("Importer un plan...", func() {
+		d := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+			if err != nil || r == nil {
+				return
+			}
+			defer r.Close()
+			donnees, err := io.ReadAll(r)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if _, err := lirePlanComptable(donnees); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if err := ecrireFichierConfig(fichierPlanComptable, donnees); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if err := c.chargerPlanComptable(); err != nil {
+				dialog.ShowError(err, w)
+			}
+			majInfo()
+		}, w)
+		d.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".txt"}))
+		d.Show()
+	})
+	btnExporter := widget.NewButton("Exporter CSV", func() {
+		t := &tableau{titre: "Plan_comptable", entetes: []string{"Compte", "Libellé"}}
+		for _, cpt := range c.pcg().rechercher(recherche.Text) {
+			t.lignes = append(t.lignes, []string{cpt.numero, cpt.libelle})
+		}
+		c.exporterFichier(t.nomFichier(".csv"), t.csv(), w)
+	})
+
+	haut := container.NewVBox(recherche, detail)
+	bas := container.NewVBox(info, container.NewGridWithColumns(3, btnRecharger, btnImporter, btnExporter))
+	w.SetContent(container.NewPadded(container.NewBorder(haut, bas, nil, nil, liste)))
+	w.Show()
+	w.Canvas().Focus(recherche)
+}
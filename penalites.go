@@ -0,0 +1,296 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// PÉNALITÉS DE RETARD ET INDEMNITÉ FORFAITAIRE
+// ========================================
+
+// Article L441-10 du Code de commerce : à défaut de taux contractuel, taux
+// de refinancement BCE + 10 points (taux en vigueur au 1er janvier pour le
+// 1er semestre, au 1er juillet pour le 2nd). Un taux contractuel ne peut être
+// inférieur à 3 fois le taux d'intérêt légal. S'y ajoute l'indemnité
+// forfaitaire pour frais de recouvrement de 40 € par facture (art. D441-5).
+
+const fichierTauxPenalites = "taux_penalites.csv"
+
+//go:embed donnees/taux_penalites.csv
+var tauxPenalitesDefaut []byte
+
+const (
+	IndemniteRecouvrement = 40.0 // Par facture payée en retard
+	MajorationBCE         = 10.0 // Points ajoutés au taux BCE
+	MultipleTauxLegal     = 3.0  // Plancher d'un taux contractuel
+	BaseJoursPenalites    = 365.0
+)
+
+const (
+	ModeTauxBCE         = "BCE + 10 points"
+	ModeTauxContractuel = "Taux contractuel"
+)
+
+// Taux en vigueur pour un semestre
+type semestreTaux struct {
+	debut     time.Time
+	tauxBCE   float64
+	tauxLegal float64
+}
+
+type tableTauxPenalites []semestreTaux
+
+func lireTauxPenalites(donnees []byte) (tableTauxPenalites, error) {
+	var table tableTauxPenalites
+	for i, champs := range lireLignesCSV(donnees) {
+		if len(champs) < 3 {
+			return nil, fmt.Errorf("%s, ligne %d : 3 colonnes attendues", fichierTauxPenalites, i+1)
+		}
+		debut, err := lireDate(champs[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s, ligne %d : %w", fichierTauxPenalites, i+1, err)
+		}
+		bce, err1 := lireNombre(champs[1])
+		legal, err2 := lireNombre(champs[2])
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("%s, ligne %d : taux invalide", fichierTauxPenalites, i+1)
+		}
+		table = append(table, semestreTaux{debut, bce, legal})
+	}
+	if len(table) == 0 {
+		return nil, fmt.Errorf("%s : aucun taux", fichierTauxPenalites)
+	}
+	sort.Slice(table, func(i, j int) bool { return table[i].debut.Before(table[j].debut) })
+	return table, nil
+}
+
+func chargerTauxPenalites() (tableTauxPenalites, error) {
+	donnees, err := lireTableConfig(fichierTauxPenalites, tauxPenalitesDefaut)
+	if err != nil {
+		return nil, err
+	}
+	return lireTauxPenalites(donnees)
+}
+
+// Taux du semestre contenant d (le dernier connu si d est postérieur).
+// Le booléen est faux si d est hors de la table.
+func (t tableTauxPenalites) semestre(d time.Time) (semestreTaux, bool) {
+	debut := debutSemestre(d)
+	for i := len(t) - 1; i >= 0; i-- {
+		if !t[i].debut.After(debut) {
+			return t[i], t[i].debut.Equal(debut)
+		}
+	}
+	return t[0], false
+}
+
+func debutSemestre(d time.Time) time.Time {
+	mois := time.January
+	if d.Month() >= time.July {
+		mois = time.July
+	}
+	return time.Date(d.Year(), mois, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Une tranche du décompte (un semestre)
+type tranchePenalite struct {
+	du, au   time.Time
+	jours    int
+	taux     float64
+	penalite float64
+	remarque string
+}
+
+// Pénalités d'une facture : montant TTC x taux x jours / 365, découpé par
+// semestre, du lendemain de l'échéance au jour du paiement inclus.
+// tauxContractuel <= 0 signifie taux BCE + 10 points.
+func calculerPenalites(montant float64, echeance, paiement time.Time, table tableTauxPenalites,
+	tauxContractuel float64, decimales int) []tranchePenalite {
+	var tranches []tranchePenalite
+	for d := echeance.AddDate(0, 0, 1); !d.After(paiement); {
+		finSemestre := debutSemestre(d).AddDate(0, 6, -1)
+		fin := paiement
+		if finSemestre.Before(fin) {
+			fin = finSemestre
+		}
+
+		sem, connu := table.semestre(d)
+		tr := tranchePenalite{du: d, au: fin, jours: joursEntre(d, fin) + 1}
+		if tauxContractuel > 0 {
+			tr.taux = tauxContractuel
+			if plancher := MultipleTauxLegal * sem.tauxLegal; tauxContractuel < plancher {
+				tr.taux = plancher
+				tr.remarque = fmt.Sprintf("plancher 3 x %s %%", formaterCle(sem.tauxLegal))
+			}
+		} else {
+			tr.taux = sem.tauxBCE + MajorationBCE
+		}
+		if !connu {
+			tr.remarque = strings.TrimPrefix(tr.remarque+", taux du semestre absent de la table", ", ")
+		}
+		tr.penalite = arrondir(montant*tr.taux/100*float64(tr.jours)/BaseJoursPenalites, decimales)
+		tranches = append(tranches, tr)
+
+		d = fin.AddDate(0, 0, 1)
+	}
+	return tranches
+}
+
+// Une facture du décompte : "référence;montant TTC;échéance;paiement"
+type factureRetard struct {
+	reference string
+	montant   float64
+	echeance  time.Time
+	paiement  time.Time
+}
+
+func lireFactureRetard(ligne string, numero int) (factureRetard, error) {
+	champs := strings.Split(ligne, ";")
+	if len(champs) == 1 {
+		champs = strings.Split(ligne, "\t")
+	}
+	if len(champs) != 4 {
+		return factureRetard{}, fmt.Errorf("ligne %d : format attendu référence;montant TTC;échéance;paiement", numero)
+	}
+	f := factureRetard{reference: strings.TrimSpace(champs[0])}
+	var err error
+	if f.montant, err = lireNombre(champs[1]); err != nil {
+		return f, fmt.Errorf("ligne %d : montant invalide", numero)
+	}
+	if f.echeance, err = lireDate(champs[2]); err != nil {
+		return f, fmt.Errorf("ligne %d : %w", numero, err)
+	}
+	if f.paiement, err = lireDate(champs[3]); err != nil {
+		return f, fmt.Errorf("ligne %d : %w", numero, err)
+	}
+	return f, nil
+}
+
+// ========================================
+// FENÊTRE PÉNALITÉS DE RETARD
+// ========================================
+
+func (c *Calculatrice) fenetrePenalites() {
+	w := c.nouvelleFenetre("Pénalités de retard", 900, 700)
+
+	selectMode := widget.NewSelect([]string{ModeTauxBCE, ModeTauxContractuel}, nil)
+	selectMode.SetSelected(ModeTauxBCE)
+	entreeTaux := widget.NewEntry()
+	entreeTaux.SetPlaceHolder("Taux annuel en %")
+	entreeTaux.Disable()
+	selectMode.OnChanged = func(mode string) {
+		if mode == ModeTauxContractuel {
+			entreeTaux.Enable()
+		} else {
+			entreeTaux.Disable()
+		}
+	}
+	checkIndemnite := widget.NewCheck("Indemnité forfaitaire de 40 € par facture", nil)
+	checkIndemnite.SetChecked(true)
+
+	entreeFactures := widget.NewMultiLineEntry()
+	entreeFactures.SetPlaceHolder("F2024-001;1200,00;31/03/2024;15/05/2024")
+	entreeFactures.SetMinRowsVisible(4)
+
+	t := &tableau{
+		titre:   "Penalites_de_retard",
+		entetes: []string{"Facture", "Montant TTC", "Du", "Au", "Jours", "Taux %", "Pénalités", "Remarque"},
+	}
+	table := nouveauTableauWidget(t)
+	resume := widget.NewLabel("")
+	resume.TextStyle = fyne.TextStyle{Bold: true}
+
+	calculer := func() {
+		tableTaux, err := chargerTauxPenalites()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		var tauxContractuel float64
+		if selectMode.Selected == ModeTauxContractuel {
+			tauxContractuel, err = lireNombre(entreeTaux.Text)
+			if err != nil || tauxContractuel <= 0 {
+				dialog.ShowError(errors.New("taux contractuel invalide"), w)
+				return
+			}
+		}
+
+		var factures []factureRetard
+		numero := 0
+		for _, ligne := range strings.Split(entreeFactures.Text, "\n") {
+			if strings.TrimSpace(ligne) == "" {
+				continue
+			}
+			numero++
+			f, err := lireFactureRetard(ligne, numero)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			factures = append(factures, f)
+		}
+		if len(factures) == 0 {
+			dialog.ShowError(errors.New("saisissez au moins une facture"), w)
+			return
+		}
+
+		decimales := c.reglages.decimales()
+		t.lignes = nil
+		var totalPenalites, totalIndemnites float64
+		for _, f := range factures {
+			if !f.paiement.After(f.echeance) {
+				t.lignes = append(t.lignes, []string{f.reference, formaterDecimales(f.montant, decimales),
+					"", "", "0", "", formaterDecimales(0, decimales), "payée à l'échéance"})
+				continue
+			}
+			for _, tr := range calculerPenalites(f.montant, f.echeance, f.paiement, tableTaux, tauxContractuel, decimales) {
+				t.lignes = append(t.lignes, []string{f.reference, formaterDecimales(f.montant, decimales),
+					formaterDate(tr.du), formaterDate(tr.au), strconv.Itoa(tr.jours), formaterCle(tr.taux),
+					formaterDecimales(tr.penalite, decimales), tr.remarque})
+				totalPenalites += tr.penalite
+			}
+			if checkIndemnite.Checked {
+				t.lignes = append(t.lignes, []string{f.reference, "", "", "", "", "",
+					formaterDecimales(IndemniteRecouvrement, decimales), "indemnité forfaitaire de recouvrement"})
+				totalIndemnites += IndemniteRecouvrement
+			}
+		}
+		total := totalPenalites + totalIndemnites
+		t.lignes = append(t.lignes, []string{"Total", "", "", "", "", "", formaterDecimales(total, decimales), ""})
+		ajusterColonnes(table, t)
+
+		resume.SetText(fmt.Sprintf("Pénalités : %s   Indemnités : %s   Total dû : %s",
+			formaterDecimales(totalPenalites, decimales), formaterDecimales(totalIndemnites, decimales),
+			formaterDecimales(total, decimales)))
+		c.afficherResultat(fmt.Sprintf("Pénalités de retard (%d facture(s), %s)", len(factures),
+			strings.ToLower(selectMode.Selected)), total)
+	}
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Taux", selectMode),
+		widget.NewFormItem("Taux contractuel", entreeTaux),
+		widget.NewFormItem("", checkIndemnite),
+		widget.NewFormItem("Factures", entreeFactures),
+	)
+	btn := widget.NewButton("Calculer le décompte", calculer)
+	btn.Importance = widget.HighImportance
+	aide := widget.NewLabel(fmt.Sprintf("Une facture par ligne : référence;montant TTC;échéance;paiement. Taux : %s",
+		cheminConfig(fichierTauxPenalites)))
+	aide.TextStyle = fyne.TextStyle{Italic: true}
+	aide.Wrapping = fyne.TextWrapWord
+
+	haut := container.NewVBox(formulaire, aide, btn, resume)
+	w.SetContent(container.NewPadded(container.NewBorder(haut, c.boutonsExport(t, w), nil, nil, table)))
+	w.Show()
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// RÉGLAGES (DEVISE, ARRONDI ESPÈCES, TAUX DE TVA)
+// ========================================
+
+const fichierReglages = "reglages.json"
+
+// Réglages enregistrés dans config/reglages.json
+type Reglages struct {
+	Devise         string          `json:"devise"`          // Code ISO de la devise de travail
+	ArrondiEspeces float64         `json:"arrondi_especes"` // Pas d'arrondi espèces (0 = aucun)
+	TauxTVA        []float64       `json:"taux_tva"`        // Taux de TVA, le taux normal en premier
+	Comptes        ComptesEcriture `json:"comptes"`         // Comptes des écritures générées
+	SIREN          string          `json:"siren"`           // SIREN de l'entreprise (nom du FEC)
+	Cloture        string          `json:"cloture"`         // Jour de clôture de l'exercice (JJ/MM)
+}
+
+func reglagesParDefaut() *Reglages {
+	return &Reglages{
+		Devise:  "EUR",
+		TauxTVA: []float64{TauxTVAStandard, TauxTVAReduit, TauxTVAReduit2, TauxTVASuper},
+		Comptes: comptesParDefaut(),
+		Cloture: "31/12",
+	}
+}
+
+// Devises proposées dans les réglages (complétées par le fichier de taux)
+var DevisesCourantes = []string{"EUR", "CHF", "USD", "GBP", "JPY", "CAD", "SEK", "NOK", "DKK", "TND", "MAD", "XOF", "XPF"}
+
+// Pas d'arrondi espèces proposés (CHF 0,05, couronnes 0,50...)
+var PasArrondiEspeces = []float64{0, 0.05, 0.10, 0.50, 1}
+
+func chargerReglages() *Reglages {
+	r := reglagesParDefaut()
+	donnees, err := os.ReadFile(cheminConfig(fichierReglages))
+	if err != nil {
+		return r
+	}
+	if err := json.Unmarshal(donnees, r); err != nil {
+		return reglagesParDefaut()
+	}
+	if r.Devise == "" {
+		r.Devise = "EUR"
+	}
+	if len(r.TauxTVA) == 0 {
+		r.TauxTVA = reglagesParDefaut().TauxTVA
+	}
+	if _, err := lireJourCloture(r.Cloture); err != nil {
+		r.Cloture = "31/12"
+	}
+	return r
+}
+
+func (r *Reglages) enregistrer() error {
+	donnees, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ecrireFichierConfig(fichierReglages, donnees)
+}
+
+// Décimales de la devise de travail
+func (r *Reglages) decimales() int {
+	return decimalesDevise(r.Devise)
+}
+
+// Taux normal de TVA (premier taux de la liste)
+func (r *Reglages) tauxNormal() float64 {
+	return r.TauxTVA[0]
+}
+
+// Taux de TVA en libellés ("20", "5,5"...), pour les listes de choix
+func (r *Reglages) libellesTauxTVA() []string {
+	var libelles []string
+	for _, taux := range r.TauxTVA {
+		libelles = append(libelles, formaterCle(taux))
+	}
+	return libelles
+}
+
+// Lit un SIREN saisi (espaces tolérés) : 9 chiffres
+func lireSIREN(s string) (string, error) {
+	siren := strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	if len(siren) != 9 || strings.Trim(siren, "0123456789") != "" {
+		return "", fmt.Errorf("SIREN invalide : %s (9 chiffres)", s)
+	}
+	return siren, nil
+}
+
+// Lit un jour de clôture "31/12" (l'année est ignorée)
+func lireJourCloture(s string) (time.Time, error) {
+	s = strings.NewReplacer(".", "/", "-", "/").Replace(strings.TrimSpace(s))
+	for _, format := range []string{"02/01", "2/1"} {
+		if d, err := time.Parse(format, s); err == nil {
+			return d, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("clôture invalide « %s » (format JJ/MM)", s)
+}
+
+// Date de clôture de l'exercice qui contient la date
+func (r *Reglages) dateCloture(date time.Time) (time.Time, error) {
+	jour, err := lireJourCloture(r.Cloture)
+	if err != nil {
+		return time.Time{}, err
+	}
+	cloture := time.Date(date.Year(), jour.Month(), jour.Day(), 0, 0, 0, 0, time.UTC)
+	if cloture.Before(date) {
+		cloture = cloture.AddDate(1, 0, 0)
+	}
+	return cloture, nil
+}
+
+// Lit une liste de taux saisie "20 ; 10 ; 5,5 ; 2,1"
+func lireListeTaux(s string) ([]float64, error) {
+	var liste []float64
+	for _, champ := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == ' ' || r == '/' }) {
+		taux, err := lireNombre(strings.TrimSuffix(champ, "%"))
+		if err != nil || taux < 0 || taux >= 100 {
+			return nil, fmt.Errorf("taux de TVA invalide : %s", champ)
+		}
+		liste = append(liste, taux)
+	}
+	if len(liste) == 0 {
+		return nil, fmt.Errorf("au moins un taux de TVA est nécessaire")
+	}
+	return liste, nil
+}
+
+// ========================================
+// ARRONDI ESPÈCES
+// ========================================
+
+// Arrondit un montant au pas espèces le plus proche (ex. 0,05 CHF).
+// Le montant est d'abord arrondi à l'unité divisionnaire de la devise.
+func arrondirEspeces(montant float64, pas float64, decimales int) float64 {
+	montant = arrondir(montant, decimales)
+	if pas <= 0 {
+		return montant
+	}
+	return arrondir(arrondir(montant/pas, 0)*pas, decimales)
+}
+
+func libellePasEspeces(pas float64) string {
+	if pas <= 0 {
+		return "Aucun"
+	}
+	return formaterDecimales(pas, 2)
+}
+
+// ========================================
+// BOÎTE DE DIALOGUE
+// ========================================
+
+func (c *Calculatrice) dialogueReglages() {
+	codes := append([]string{}, DevisesCourantes...)
+	if c.tauxChanges != nil {
+		for _, code := range c.tauxChanges.devises() {
+			if !slices.Contains(codes, code) {
+				codes = append(codes, code)
+			}
+		}
+		sort.Strings(codes[len(DevisesCourantes):])
+	}
+
+	selectDevise := widget.NewSelect(codes, nil)
+	selectDevise.SetSelected(c.reglages.Devise)
+
+	var libellesPas []string
+	for _, pas := range PasArrondiEspeces {
+		libellesPas = append(libellesPas, libellePasEspeces(pas))
+	}
+	selectPas := widget.NewSelect(libellesPas, nil)
+	selectPas.SetSelected(libellePasEspeces(c.reglages.ArrondiEspeces))
+
+	infoDecimales := widget.NewLabel("")
+	selectDevise.OnChanged = func(code string) {
+		infoDecimales.SetText(fmt.Sprintf("%d décimale(s)", decimalesDevise(code)))
+	}
+	selectDevise.OnChanged(c.reglages.Devise)
+
+	entreeTauxTVA := widget.NewEntry()
+	entreeTauxTVA.SetText(strings.Join(c.reglages.libellesTauxTVA(), " ; "))
+
+	entreeSIREN := widget.NewEntry()
+	entreeSIREN.SetPlaceHolder("9 chiffres, pour le nom du FEC")
+	entreeSIREN.SetText(c.reglages.SIREN)
+	entreeCloture := widget.NewEntry()
+	entreeCloture.SetText(c.reglages.Cloture)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Devise", selectDevise),
+		widget.NewFormItem("Unité", infoDecimales),
+		widget.NewFormItem("Arrondi espèces", selectPas),
+		widget.NewFormItem("Taux de TVA", entreeTauxTVA),
+		widget.NewFormItem("SIREN", entreeSIREN),
+		widget.NewFormItem("Clôture (JJ/MM)", entreeCloture),
+	}
+
+	dialog.ShowForm("Réglages", "Enregistrer", "Annuler", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		tauxTVA, err := lireListeTaux(entreeTauxTVA.Text)
+		if err != nil {
+			dialog.ShowError(err, c.fenetre)
+			return
+		}
+		siren := ""
+		if strings.TrimSpace(entreeSIREN.Text) != "" {
+			if siren, err = lireSIREN(entreeSIREN.Text); err != nil {
+				dialog.ShowError(err, c.fenetre)
+				return
+			}
+		}
+		jour, err := lireJourCloture(entreeCloture.Text)
+		if err != nil {
+			dialog.ShowError(err, c.fenetre)
+			return
+		}
+		c.reglages.TauxTVA = tauxTVA
+		c.reglages.SIREN = siren
+		c.reglages.Cloture = jour.Format("02/01")
+		c.reglages.Devise = selectDevise.Selected
+		if i := selectPas.SelectedIndex(); i >= 0 {
+			c.reglages.ArrondiEspeces = PasArrondiEspeces[i]
+		}
+		if err := c.reglages.enregistrer(); err != nil {
+			dialog.ShowError(err, c.fenetre)
+		}
+		c.majInfoEcran()
+		c.majBoutonsTVA()
+		c.mettreAJourAffichage()
+	}, c.fenetre)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// RÉPARTITION AU PRORATA (SANS PERTE DE CENTIMES)
+// ========================================
+
+// Affectation du reste d'arrondi (les centimes non répartis)
+const (
+	RestePlusForts       = "Plus forts restes"
+	ResteDerniereLigne   = "Dernière ligne"
+	RestePlusGrosseLigne = "Plus grosse ligne"
+)
+
+var MethodesRepartition = []string{RestePlusForts, ResteDerniereLigne, RestePlusGrosseLigne}
+
+// Répartit un total selon des poids (clés ou pourcentages) de sorte que la
+// somme des parts arrondies soit exactement égale au total.
+// Le calcul se fait en unités divisionnaires entières (centimes).
+func repartir(total float64, poids []float64, decimales int, methode string) ([]float64, error) {
+	if len(poids) == 0 {
+		return nil, errors.New("aucune clé de répartition")
+	}
+	var somme float64
+	for _, p := range poids {
+		if p < 0 {
+			return nil, errors.New("les clés de répartition doivent être positives")
+		}
+		somme += p
+	}
+	if somme == 0 {
+		return nil, errors.New("la somme des clés est nulle")
+	}
+
+	unite := math.Pow10(decimales)
+	totalUnites := int64(math.Round(arrondir(total, decimales) * unite))
+	sens := int64(1)
+	if totalUnites < 0 {
+		sens, totalUnites = -1, -totalUnites
+	}
+
+	parts := make([]int64, len(poids))
+	restes := make([]float64, len(poids))
+	var reparti int64
+	for i, p := range poids {
+		exact := float64(totalUnites) * p / somme
+		parts[i] = int64(math.Floor(exact + 1e-9))
+		restes[i] = exact - float64(parts[i])
+		reparti += parts[i]
+	}
+
+	reste := totalUnites - reparti
+	switch methode {
+	case ResteDerniereLigne:
+		parts[len(parts)-1] += reste
+	case RestePlusGrosseLigne:
+		plusGrosse := 0
+		for i := range parts {
+			if parts[i] > parts[plusGrosse] {
+				plusGrosse = i
+			}
+		}
+		parts[plusGrosse] += reste
+	default:
+		// Méthode des plus forts restes (à égalité, la première ligne)
+		ordre := make([]int, len(parts))
+		for i := range ordre {
+			ordre[i] = i
+		}
+		sort.SliceStable(ordre, func(a, b int) bool { return restes[ordre[a]] > restes[ordre[b]] })
+		for k := int64(0); k < reste; k++ {
+			parts[ordre[k]]++
+		}
+	}
+
+	resultats := make([]float64, len(parts))
+	for i, p := range parts {
+		resultats[i] = float64(sens*p) / unite
+	}
+	return resultats, nil
+}
+
+// Une ligne de clé : "libellé;valeur", "libellé<TAB>valeur" ou "valeur" (le % final est ignoré)
+func lireLigneCle(ligne string, numero int) (string, float64, error) {
+	ligne = strings.TrimSpace(ligne)
+	libelle := fmt.Sprintf("Ligne %d", numero)
+	valeur := ligne
+	if i := strings.LastIndexAny(ligne, ";\t"); i >= 0 {
+		libelle = strings.TrimSpace(ligne[:i])
+		valeur = ligne[i+1:]
+	}
+	n, err := lireNombre(strings.TrimSuffix(strings.TrimSpace(valeur), "%"))
+	if err != nil {
+		return "", 0, fmt.Errorf("ligne %d : clé invalide « %s »", numero, valeur)
+	}
+	return libelle, n, nil
+}
+
+// Clé sans zéros inutiles ni résidus flottants (33,33 et non 33,329999...)
+func formaterCle(n float64) string {
+	return strings.ReplaceAll(strconv.FormatFloat(arrondir(n, 6), 'f', -1, 64), ".", ",")
+}
+
+// ========================================
+// FENÊTRE DE RÉPARTITION
+// ========================================
+
+func (c *Calculatrice) fenetreRepartition() {
+	w := c.nouvelleFenetre("Répartition au prorata", 800, 650)
+
+	entreeTotal := widget.NewEntry()
+	if v := c.obtenirValeurCourante(); v != 0 {
+		entreeTotal.SetText(c.formaterResultat(v))
+	}
+
+	selectType := widget.NewSelect([]string{"Clés", "Pourcentages"}, nil)
+	selectType.SetSelected("Clés")
+	selectMethode := widget.NewSelect(MethodesRepartition, nil)
+	selectMethode.SetSelected(RestePlusForts)
+
+	entreeCles := widget.NewMultiLineEntry()
+	entreeCles.SetPlaceHolder("Centre A;1\nCentre B;1\nCentre C;1")
+	entreeCles.SetMinRowsVisible(6)
+
+	t := &tableau{
+		titre:   "Repartition",
+		entetes: []string{"Ligne", "Clé", "Part exacte", "Montant", "Écart"},
+	}
+	table := nouveauTableauWidget(t)
+	resume := widget.NewLabel("")
+
+	repartirClic := func() {
+		total, err := lireNombre(entreeTotal.Text)
+		if err != nil {
+			dialog.ShowError(errors.New("montant à répartir invalide"), w)
+			return
+		}
+
+		var libelles []string
+		var poids []float64
+		numero := 0
+		for _, ligne := range strings.Split(entreeCles.Text, "\n") {
+			if strings.TrimSpace(ligne) == "" {
+				continue
+			}
+			numero++
+			libelle, p, err := lireLigneCle(ligne, numero)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			libelles = append(libelles, libelle)
+			poids = append(poids, p)
+		}
+
+		var somme float64
+		for _, p := range poids {
+			somme += p
+		}
+		if selectType.Selected == "Pourcentages" && math.Abs(somme-100) > 1e-9 {
+			dialog.ShowError(fmt.Errorf("la somme des pourcentages fait %s %% au lieu de 100 %%",
+				formaterCle(somme)), w)
+			return
+		}
+
+		decimales := c.reglages.decimales()
+		parts, err := repartir(total, poids, decimales, selectMethode.Selected)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		t.lignes = nil
+		var sommeParts float64
+		for i, part := range parts {
+			exacte := total * poids[i] / somme
+			t.lignes = append(t.lignes, []string{
+				libelles[i],
+				formaterCle(poids[i]),
+				formaterDecimales(exacte, decimales+4),
+				formaterDecimales(part, decimales),
+				formaterDecimales(part-exacte, decimales+4),
+			})
+			sommeParts += part
+		}
+		t.lignes = append(t.lignes, []string{"Total", formaterCle(somme),
+			formaterDecimales(total, decimales+4), formaterDecimales(sommeParts, decimales), ""})
+		ajusterColonnes(table, t)
+
+		resume.SetText(fmt.Sprintf("%d parts, reste affecté : %s", len(parts), strings.ToLower(selectMethode.Selected)))
+
+		for i, part := range parts {
+			c.ajouterHistorique(fmt.Sprintf("%s : %s/%s de %s = %s", libelles[i],
+				formaterCle(poids[i]), formaterCle(somme),
+				c.formaterResultat(total), formaterDecimales(part, decimales)))
+		}
+	}
+
+	formulaire := widget.NewForm(
+		widget.NewFormItem("Montant", entreeTotal),
+		widget.NewFormItem("Clés en", selectType),
+		widget.NewFormItem("Reste", selectMethode),
+		widget.NewFormItem("Lignes", entreeCles),
+	)
+	btnRepartir := widget.NewButton("Répartir", repartirClic)
+	btnRepartir.Importance = widget.HighImportance
+
+	haut := container.NewVBox(formulaire, btnRepartir, resume)
+	w.SetContent(container.NewPadded(container.NewBorder(haut, c.boutonsExport(t, w), nil, nil, table)))
+	w.Canvas().Focus(entreeCles)
+	w.Show()
+}
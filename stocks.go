@@ -0,0 +1,290 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// FICHE DE STOCK (CMUP ET PEPS)
+// ========================================
+
+// Les sorties sont valorisées au coût moyen unitaire pondéré recalculé après
+// chaque entrée, au coût moyen de la période (stock initial + entrées), ou
+// par lots dans l'ordre d'entrée (premier entré, premier sorti).
+
+const (
+	MethodeCMUPEntree  = "CMUP après chaque entrée"
+	MethodeCMUPPeriode = "CMUP de fin de période"
+	MethodePEPS        = "Premier entré, premier sorti (FIFO)"
+)
+
+var MethodesStock = []string{MethodeCMUPEntree, MethodeCMUPPeriode, MethodePEPS}
+
+const (
+	MouvementEntree = "Entrée"
+	MouvementSortie = "Sortie"
+)
+
+type mouvementStock struct {
+	date         time.Time
+	libelle      string
+	entree       bool
+	quantite     float64
+	coutUnitaire float64 // Entrées seulement
+}
+
+type ligneFicheStock struct {
+	mouvementStock
+	montant               float64 // Valeur de l'entrée ou de la sortie
+	stockQte, stockValeur float64
+}
+
+// Coût unitaire du mouvement (moyen pour une sortie PEPS sur plusieurs lots)
+func (l ligneFicheStock) cout() float64 {
+	if l.quantite == 0 {
+		return 0
+	}
+	return l.montant / l.quantite
+}
+
+type lotStock struct {
+	quantite, cout float64
+}
+
+// Fiche de stock des mouvements triés par date ; erreur si une sortie
+// dépasse le stock disponible
+func ficheStock(mouvements []mouvementStock, methode string, decimales int) ([]ligneFicheStock, error) {
+	var cmupPeriode float64
+	if methode == MethodeCMUPPeriode {
+		var qte, valeur float64
+		for _, m := range mouvements {
+			if m.entree {
+				qte += m.quantite
+				valeur += arrondir(m.quantite*m.coutUnitaire, decimales)
+			}
+		}
+		if qte > 0 {
+			cmupPeriode = valeur / qte
+		}
+	}
+
+	var lignes []ligneFicheStock
+	var lots []lotStock
+	var qte, valeur float64
+	for i, m := range mouvements {
+		l := ligneFicheStock{mouvementStock: m}
+		if m.entree {
+			l.montant = arrondir(m.quantite*m.coutUnitaire, decimales)
+			qte += m.quantite
+			valeur = arrondir(valeur+l.montant, decimales)
+			lots = append(lots, lotStock{m.quantite, m.coutUnitaire})
+		} else {
+			if m.quantite > qte+1e-9 {
+				return nil, fmt.Errorf("sortie du %s : %s demandés, %s en stock",
+					formaterDate(m.date), formaterCle(m.quantite), formaterCle(qte))
+			}
+			switch {
+			case methode == MethodeCMUPPeriode:
+				// Toutes les sorties au coût de la période, même celle qui solde
+				l.montant = arrondir(m.quantite*cmupPeriode, decimales)
+			case m.quantite >= qte-1e-9:
+				// Le stock est soldé : la sortie reprend toute sa valeur
+				l.montant = valeur
+				lots = nil
+			case methode == MethodeCMUPEntree:
+				l.montant = arrondir(m.quantite*valeur/qte, decimales)
+			default:
+				reste := m.quantite
+				for reste > 1e-9 {
+					pris := reste
+					if lots[0].quantite < pris {
+						pris = lots[0].quantite
+					}
+					l.montant += pris * lots[0].cout
+					lots[0].quantite -= pris
+					reste -= pris
+					if lots[0].quantite <= 1e-9 {
+						lots = lots[1:]
+					}
+				}
+				l.montant = arrondir(l.montant, decimales)
+			}
+			qte -= m.quantite
+			valeur = arrondir(valeur-l.montant, decimales)
+		}
+		l.stockQte, l.stockValeur = qte, valeur
+		if methode == MethodeCMUPPeriode && i < len(mouvements)-1 {
+			// Stock en cours de période au coût de la période ; l'écart
+			// d'arrondi des sorties reste dans le stock final
+			l.stockValeur = arrondir(qte*cmupPeriode, decimales)
+		}
+		lignes = append(lignes, l)
+	}
+	return lignes, nil
+}
+
+// Coût total des sorties et stock final
+func totauxStock(lignes []ligneFicheStock) (sorties, stock float64) {
+	for _, l := range lignes {
+		if !l.entree {
+			sorties += l.montant
+		}
+	}
+	if len(lignes) > 0 {
+		stock = lignes[len(lignes)-1].stockValeur
+	}
+	return sorties, stock
+}
+
+// ========================================
+// FENÊTRE FICHE DE STOCK
+// ========================================
+
+func (c *Calculatrice) fenetreStock() {
+	w := c.nouvelleFenetre("Fiche de stock", 1100, 700)
+	decimales := c.reglages.decimales()
+	f := func(n float64) string { return formaterDecimales(n, decimales) }
+
+	var mouvements []mouvementStock // Ordre de saisie, pour "Supprimer le dernier"
+
+	entreeDate := widget.NewEntry()
+	entreeDate.SetText(formaterDate(aujourdhui()))
+	entreeLibelle := widget.NewEntry()
+	entreeLibelle.SetPlaceHolder("Libellé (facture, bon de sortie...)")
+	entreeQuantite := widget.NewEntry()
+	entreeQuantite.SetPlaceHolder("Quantité")
+	entreeCout := widget.NewEntry()
+	entreeCout.SetPlaceHolder("Coût unitaire")
+	selectType := widget.NewSelect([]string{MouvementEntree, MouvementSortie}, func(s string) {
+		if s == MouvementSortie {
+			entreeCout.Disable()
+		} else {
+			entreeCout.Enable()
+		}
+	})
+	selectType.SetSelected(MouvementEntree)
+	selectMethode := widget.NewSelect(MethodesStock, nil)
+
+	resume := widget.NewLabel("")
+	resume.TextStyle = fyne.TextStyle{Bold: true}
+	resume.Wrapping = fyne.TextWrapWord
+	t := &tableau{
+		titre: "Fiche_de_stock",
+		entetes: []string{"Date", "Libellé", "Qté entrée", "Coût entrée", "Montant entrée",
+			"Qté sortie", "Coût sortie", "Montant sortie", "Qté stock", "Coût stock", "Valeur stock"},
+	}
+	table := nouveauTableauWidget(t)
+
+	var courantes []ligneFicheStock
+	maj := func() {
+		t.lignes = nil
+		courantes = nil
+		// Ordre chronologique, ordre de saisie dans la journée
+		tries := append([]mouvementStock(nil), mouvements...)
+		sort.SliceStable(tries, func(i, j int) bool { return tries[i].date.Before(tries[j].date) })
+		lignes, err := ficheStock(tries, selectMethode.Selected, decimales)
+		if err != nil {
+			resume.SetText(err.Error())
+			resume.Importance = widget.DangerImportance
+			resume.Refresh()
+			ajusterColonnes(table, t)
+			return
+		}
+		courantes = lignes
+		for _, l := range lignes {
+			ligne := []string{formaterDate(l.date), l.libelle, "", "", "", "", "", ""}
+			if l.entree {
+				ligne[2], ligne[3], ligne[4] = formaterCle(l.quantite), formaterDecimales(l.cout(), 4), f(l.montant)
+			} else {
+				ligne[5], ligne[6], ligne[7] = formaterCle(l.quantite), formaterDecimales(l.cout(), 4), f(l.montant)
+			}
+			coutStock := ""
+			if l.stockQte > 1e-9 {
+				coutStock = formaterDecimales(l.stockValeur/l.stockQte, 4)
+			}
+			t.lignes = append(t.lignes, append(ligne, formaterCle(l.stockQte), coutStock, f(l.stockValeur)))
+		}
+		ajusterColonnes(table, t)
+
+		if len(mouvements) == 0 {
+			resume.SetText("")
+			return
+		}
+		// Comparaison des trois méthodes
+		var comparaison []string
+		for _, methode := range MethodesStock {
+			if lignes, err := ficheStock(tries, methode, decimales); err == nil {
+				sorties, stock := totauxStock(lignes)
+				comparaison = append(comparaison, fmt.Sprintf("%s : sorties %s, stock %s", methode, f(sorties), f(stock)))
+			}
+		}
+		resume.SetText(strings.Join(comparaison, "\n"))
+		resume.Importance = widget.MediumImportance
+		resume.Refresh()
+	}
+	selectMethode.OnChanged = func(string) { maj() }
+	selectMethode.SetSelected(MethodeCMUPEntree)
+
+	btnAjouter := widget.NewButton("Ajouter", func() {
+		date, err := lireDate(entreeDate.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		quantite, err := lireNombre(entreeQuantite.Text)
+		if err != nil || quantite <= 0 {
+			dialog.ShowError(errors.New("quantité invalide"), w)
+			return
+		}
+		m := mouvementStock{date: date, libelle: strings.TrimSpace(entreeLibelle.Text),
+			entree: selectType.Selected == MouvementEntree, quantite: quantite}
+		if m.entree {
+			if m.coutUnitaire, err = lireNombre(entreeCout.Text); err != nil || m.coutUnitaire < 0 {
+				dialog.ShowError(errors.New("coût unitaire invalide"), w)
+				return
+			}
+		}
+		mouvements = append(mouvements, m)
+		entreeLibelle.SetText("")
+		entreeQuantite.SetText("")
+		entreeCout.SetText("")
+		maj()
+		w.Canvas().Focus(entreeQuantite)
+	})
+	btnAjouter.Importance = widget.HighImportance
+	// Retire le dernier mouvement saisi, quelle que soit sa date
+	btnSupprimer := widget.NewButton("Supprimer le dernier", func() {
+		if len(mouvements) > 0 {
+			mouvements = mouvements[:len(mouvements)-1]
+			maj()
+		}
+	})
+	btnVider := widget.NewButton("Vider", func() {
+		mouvements = nil
+		maj()
+	})
+	btnHistorique := widget.NewButton("Vers l'historique", func() {
+		if len(courantes) == 0 {
+			return
+		}
+		sorties, stock := totauxStock(courantes)
+		c.ajouterHistorique(fmt.Sprintf("Coût des sorties (%s) = %s", selectMethode.Selected, f(sorties)))
+		c.afficherResultat(fmt.Sprintf("Stock final (%s)", selectMethode.Selected), stock)
+	})
+
+	saisie := container.NewGridWithColumns(5, entreeDate, selectType, entreeLibelle, entreeQuantite, entreeCout)
+	boutons := container.NewGridWithColumns(4, btnAjouter, btnSupprimer, btnVider, btnHistorique)
+	methode := widget.NewForm(widget.NewFormItem("Méthode", selectMethode))
+	haut := container.NewVBox(saisie, boutons, methode, resume)
+	w.SetContent(container.NewPadded(container.NewBorder(haut, c.boutonsExport(t, w), nil, nil, table)))
+	w.Show()
+}
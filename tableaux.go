@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// TABLEAUX DE RÉSULTATS (COPIE TSV, EXPORT CSV)
+// ========================================
+
+// Tableau de résultats affichable, copiable et exportable
+type tableau struct {
+	titre   string
+	entetes []string
+	lignes  [][]string
+}
+
+// Texte séparé par des tabulations, à coller dans un tableur
+func (t *tableau) tsv() string {
+	var b strings.Builder
+	b.WriteString(strings.Join(t.entetes, "\t"))
+	for _, ligne := range t.lignes {
+		b.WriteString("\n")
+		b.WriteString(strings.Join(ligne, "\t"))
+	}
+	return b.String()
+}
+
+// CSV au format Excel français : séparateur ";" et BOM UTF-8
+func (t *tableau) csv() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+	w.UseCRLF = true
+	w.Write(t.entetes)
+	w.WriteAll(t.lignes)
+	return buf.Bytes()
+}
+
+// Nom de fichier proposé à l'export (titre sans caractères spéciaux)
+func (t *tableau) nomFichier(extension string) string {
+	nom := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r == ' ', r == '_':
+			return '_'
+		}
+		return -1
+	}, t.titre)
+	if nom == "" {
+		nom = "export"
+	}
+	return nom + extension
+}
+
+// Widget de tableau (ligne d'en-tête en gras), rafraîchi quand t change
+func nouveauTableauWidget(t *tableau) *widget.Table {
+	table := widget.NewTable(
+		func() (int, int) { return len(t.lignes) + 1, len(t.entetes) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			if id.Row == 0 {
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				label.SetText(t.entetes[id.Col])
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			ligne := t.lignes[id.Row-1]
+			if id.Col < len(ligne) {
+				label.SetText(ligne[id.Col])
+			} else {
+				label.SetText("")
+			}
+		},
+	)
+	ajusterColonnes(table, t)
+	return table
+}
+
+// Largeur des colonnes selon le texte le plus long
+func ajusterColonnes(table *widget.Table, t *tableau) {
+	for col, entete := range t.entetes {
+		longueur := utf8.RuneCountInString(entete)
+		for _, ligne := range t.lignes {
+			if col < len(ligne) && utf8.RuneCountInString(ligne[col]) > longueur {
+				longueur = utf8.RuneCountInString(ligne[col])
+			}
+		}
+		table.SetColumnWidth(col, float32(longueur)*11+30)
+	}
+	table.Refresh()
+}
+
+// Boutons "Copier (TSV)" et "Exporter CSV" pour un tableau
+func (c *Calculatrice) boutonsExport(t *tableau, parent fyne.Window) fyne.CanvasObject {
+	btnCopier := widget.NewButton("Copier (TSV)", func() {
+		parent.Clipboard().SetContent(t.tsv())
+	})
+	btnExporter := widget.NewButton("Exporter CSV", func() {
+		c.exporterFichier(t.nomFichier(".csv"), t.csv(), parent)
+	})
+	return container.NewGridWithColumns(2, btnCopier, btnExporter)
+}
+
+// Enregistre un contenu via la boîte de dialogue de sauvegarde
+func (c *Calculatrice) exporterFichier(nom string, contenu []byte, parent fyne.Window) {
+	d := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil || w == nil {
+			return
+		}
+		defer w.Close()
+		if _, err := w.Write(contenu); err != nil {
+			dialog.ShowError(err, parent)
+		}
+	}, parent)
+	d.SetFileName(nom)
+	d.Show()
+}
+
+// Crée une fenêtre secondaire (le contenu est ajouté par l'appelant)
+func (c *Calculatrice) nouvelleFenetre(titre string, largeur, hauteur float32) fyne.Window {
+	w := fyne.CurrentApp().NewWindow(titre)
+	w.SetIcon(c.fenetre.Icon())
+	w.Resize(fyne.NewSize(largeur, hauteur))
+	return w
+}
+
+// Ouvre un tableau de résultats dans sa propre fenêtre
+func (c *Calculatrice) afficherTableau(t *tableau) {
+	w := c.nouvelleFenetre(t.titre, 800, 500)
+	table := nouveauTableauWidget(t)
+	w.SetContent(container.NewPadded(container.NewBorder(nil, c.boutonsExport(t, w), nil, nil, table)))
+	w.Show()
+}
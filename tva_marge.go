@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ========================================
+// TVA SUR LA MARGE (BIENS D'OCCASION, AGENCES DE VOYAGES)
+// ========================================
+
+// La marge est calculée TVA comprise : TVA = marge TTC - marge TTC / (1 + taux).
+// Opération par opération, une marge négative ne donne pas de TVA et n'est
+// pas reportable. Au régime global, la marge se calcule sur la période
+// (ventes - achats) et une marge négative s'ajoute aux achats de la période
+// suivante.
+
+const (
+	ModeMargeOperation = "Opération par opération"
+	ModeMargeGlobal    = "Régime global (période)"
+)
+
+type resultatMarge struct {
+	margeTTC, margeHT, tva float64
+	report                 float64 // Marge négative reportée (régime global)
+}
+
+func calculerTVAMarge(ventes, achats, reportAnterieur, taux float64, global bool, decimales int) resultatMarge {
+	marge := arrondir(ventes-achats, decimales)
+	if global {
+		marge = arrondir(marge-reportAnterieur, decimales)
+	}
+	if marge <= 0 {
+		r := resultatMarge{margeTTC: marge}
+		if global {
+			r.report = -marge
+		}
+		return r
+	}
+	ht := arrondir(marge/(1+taux/100), decimales)
+	return resultatMarge{margeTTC: marge, margeHT: ht, tva: arrondir(marge-ht, decimales)}
+}
+
+// ========================================
+// BOÎTE DE DIALOGUE TVA SUR MARGE
+// ========================================
+
+func (c *Calculatrice) dialogueTVAMarge() {
+	entreeAchat := widget.NewEntry()
+	entreeAchat.SetPlaceHolder("Prix d'achat TTC")
+	entreeVente := widget.NewEntry()
+	entreeVente.SetPlaceHolder("Prix de vente TTC")
+	if v := c.obtenirValeurCourante(); v > 0 {
+		entreeVente.SetText(c.formaterNombre(c.valeurCourante))
+	}
+	entreeReport := widget.NewEntry()
+	entreeReport.SetPlaceHolder("Marge négative de la période précédente")
+	entreeReport.Disable()
+
+	selectMode := widget.NewSelect([]string{ModeMargeOperation, ModeMargeGlobal}, func(mode string) {
+		if mode == ModeMargeGlobal {
+			entreeAchat.SetPlaceHolder("Achats TTC de la période")
+			entreeVente.SetPlaceHolder("Ventes TTC de la période")
+			entreeReport.Enable()
+		} else {
+			entreeAchat.SetPlaceHolder("Prix d'achat TTC")
+			entreeVente.SetPlaceHolder("Prix de vente TTC")
+			entreeReport.Disable()
+		}
+	})
+	selectMode.SetSelected(ModeMargeOperation)
+	selectTaux := widget.NewSelect(c.reglages.libellesTauxTVA(), nil)
+	selectTaux.SetSelectedIndex(0)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Mode", selectMode),
+		widget.NewFormItem("Achat", entreeAchat),
+		widget.NewFormItem("Vente", entreeVente),
+		widget.NewFormItem("Report", entreeReport),
+		widget.NewFormItem("Taux de TVA", selectTaux),
+	}
+
+	dialog.ShowForm("TVA sur marge", "Calculer", "Annuler", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		achat, err := lireNombre(entreeAchat.Text)
+		if err != nil || achat < 0 {
+			dialog.ShowError(errors.New("montant d'achat invalide"), c.fenetre)
+			return
+		}
+		vente, err := lireNombre(entreeVente.Text)
+		if err != nil || vente < 0 {
+			dialog.ShowError(errors.New("montant de vente invalide"), c.fenetre)
+			return
+		}
+		global := selectMode.Selected == ModeMargeGlobal
+		var report float64
+		if global && strings.TrimSpace(entreeReport.Text) != "" {
+			if report, err = lireNombre(entreeReport.Text); err != nil || report < 0 {
+				dialog.ShowError(errors.New("report invalide"), c.fenetre)
+				return
+			}
+		}
+		taux, _ := lireNombre(selectTaux.Selected)
+
+		decimales := c.reglages.decimales()
+		r := calculerTVAMarge(vente, achat, report, taux, global, decimales)
+		f := func(n float64) string { return formaterDecimales(n, decimales) }
+		operation := fmt.Sprintf("%s - %s", f(vente), f(achat))
+		if global && report != 0 {
+			operation += " - " + f(report) + " reporté"
+		}
+
+		if r.margeTTC <= 0 {
+			if global {
+				c.ajouterHistorique(fmt.Sprintf("Marge globale négative %s : à reporter sur la période suivante = %s", operation, f(r.report)))
+			} else {
+				c.ajouterHistorique(fmt.Sprintf("Marge négative %s : pas de TVA, non reportable = %s", operation, f(r.margeTTC)))
+			}
+			c.afficherResultat(fmt.Sprintf("TVA sur marge (%s)", operation), 0)
+			return
+		}
+		c.ajouterHistorique(fmt.Sprintf("Marge TTC %s = %s", operation, f(r.margeTTC)))
+		c.ajouterHistorique(fmt.Sprintf("Marge HT %s / %s = %s", f(r.margeTTC), formaterCle(1+taux/100), f(r.margeHT)))
+		c.afficherResultat(fmt.Sprintf("TVA sur marge %s %% (%s - %s)", formaterCle(taux), f(r.margeTTC), f(r.margeHT)), r.tva)
+	}, c.fenetre)
+}